@@ -30,3 +30,25 @@ func BenchmarkGoStrftime(b *testing.B) {
 		strftime.Format(now, layout)
 	}
 }
+
+func BenchmarkGoStrftimeCompiled(b *testing.B) {
+	l := strftime.MustCompile("%Y-%m-%dT%H:%M:%S.%f%z")
+	now := time.Now()
+	for i := 0; i < b.N; i++ {
+		l.Format(now)
+	}
+}
+
+func BenchmarkGoStrftimeComposite(b *testing.B) {
+	layouts := []string{"%c", "%D", "%F", "%r", "%R", "%T", "%x", "%X", "%n%t%%", "%F %T"}
+	now := time.Now()
+	for _, layout := range layouts {
+		b.Run(layout, func(b *testing.B) {
+			var buf [64]byte
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				strftime.AppendFormat(buf[:0], now, layout)
+			}
+		})
+	}
+}
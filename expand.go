@@ -0,0 +1,45 @@
+// Copyright 2018 Timon Wong. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package strftime
+
+import "time"
+
+// ExpandAll returns every distinct output of Format(t, layout) for t from
+// from up to, but not including, to, in order of first appearance. Times
+// are taken in the location of from.
+//
+// It steps from bucket to bucket with NextChange, so outputs skipped by a
+// daylight saving time gap are left out, and those repeated when clocks are
+// set back are returned once:
+//
+//	strftime.ExpandAll("events/%Y/%m/%d/%H/", from, to)
+//
+// Expand is its iterator form from Go 1.23 on.
+func ExpandAll(layout string, from, to time.Time) []string {
+	var all []string
+	expand(layout, from, to, func(s string) bool {
+		all = append(all, s)
+		return true
+	})
+	return all
+}
+
+// expand calls yield with each output of ExpandAll until it returns false.
+func expand(layout string, from, to time.Time, yield func(string) bool) {
+	g := layoutGranularity(layout)
+	seen := make(map[string]struct{})
+	for t := from; t.Before(to); {
+		s := Format(t, layout)
+		if _, ok := seen[s]; !ok {
+			seen[s] = struct{}{}
+			if !yield(s) {
+				return
+			}
+		}
+		if t = g.next(t, layout); t.IsZero() {
+			return // the output never changes again
+		}
+	}
+}
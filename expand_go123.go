@@ -0,0 +1,25 @@
+// Copyright 2018 Timon Wong. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build go1.23
+// +build go1.23
+
+package strftime
+
+import (
+	"iter"
+	"time"
+)
+
+// Expand returns an iterator over the outputs of ExpandAll, computed as
+// they are consumed:
+//
+//	for path := range strftime.Expand("events/%Y/%m/%d/%H/", from, to) {
+//		...
+//	}
+func Expand(layout string, from, to time.Time) iter.Seq[string] {
+	return func(yield func(string) bool) {
+		expand(layout, from, to, yield)
+	}
+}
@@ -0,0 +1,41 @@
+// Copyright 2018 Timon Wong. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build go1.23
+// +build go1.23
+
+package strftime_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/imperfectgo/go-strftime"
+)
+
+func TestExpand(t *testing.T) {
+	from := time.Date(2024, 1, 30, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	var actual []string
+	for s := range strftime.Expand("%Y-%m", from, to) {
+		actual = append(actual, s)
+	}
+	if expected := []string{"2024-01", "2024-02"}; !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected: %q; actual: %q", expected, actual)
+	}
+
+	// Stopping early.
+	actual = actual[:0]
+	for s := range strftime.Expand("%F", from, to) {
+		actual = append(actual, s)
+		if len(actual) == 2 {
+			break
+		}
+	}
+	if expected := []string{"2024-01-30", "2024-01-31"}; !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected: %q; actual: %q", expected, actual)
+	}
+}
@@ -0,0 +1,119 @@
+// Copyright 2018 Timon Wong. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package strftime_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/imperfectgo/go-strftime"
+)
+
+func TestExpandAll(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip("time zone database unavailable:", err)
+	}
+	hours := make([]string, 24)
+	for h := range hours {
+		hours[h] = strftime.Format(time.Date(2024, 1, 1, h, 0, 0, 0, time.UTC), "%H")
+	}
+	minutes := make([]string, 60)
+	for m := range minutes {
+		minutes[m] = strftime.Format(time.Date(2024, 1, 1, 1, m, 0, 0, time.UTC), "%H:%M")
+	}
+
+	tests := []struct {
+		layout   string
+		from, to time.Time
+		expected []string
+	}{
+		{
+			layout:   "events/%Y/%m/%d/%H/",
+			from:     time.Date(2024, 1, 1, 22, 30, 0, 0, time.UTC),
+			to:       time.Date(2024, 1, 2, 1, 0, 0, 0, time.UTC),
+			expected: []string{"events/2024/01/01/22/", "events/2024/01/01/23/", "events/2024/01/02/00/"},
+		},
+		{
+			// 02:00 is skipped.
+			layout:   "%Y%m%d%H",
+			from:     time.Date(2024, 3, 10, 0, 0, 0, 0, newYork),
+			to:       time.Date(2024, 3, 10, 5, 0, 0, 0, newYork),
+			expected: []string{"2024031000", "2024031001", "2024031003", "2024031004"},
+		},
+		{
+			// 01:00 to 02:00 repeats.
+			layout:   "%Y%m%d%H",
+			from:     time.Date(2024, 11, 3, 0, 0, 0, 0, newYork),
+			to:       time.Date(2024, 11, 3, 3, 0, 0, 0, newYork),
+			expected: []string{"2024110300", "2024110301", "2024110302"},
+		},
+		{
+			layout:   "%H:%M",
+			from:     time.Date(2024, 11, 3, 5, 0, 0, 0, time.UTC).In(newYork), // 01:00 EDT
+			to:       time.Date(2024, 11, 3, 6, 2, 0, 0, time.UTC),             // 01:02 EST
+			expected: minutes,
+		},
+		{
+			layout:   "%H",
+			from:     time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+			to:       time.Date(2024, 1, 3, 0, 0, 0, 0, time.UTC),
+			expected: hours,
+		},
+		{
+			layout:   "%Y%m%d %Z",
+			from:     time.Date(2024, 3, 9, 12, 0, 0, 0, newYork),
+			to:       time.Date(2024, 3, 10, 12, 0, 0, 0, newYork),
+			expected: []string{"20240309 EST", "20240310 EST", "20240310 EDT"},
+		},
+		{
+			layout:   "app.log",
+			from:     time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+			to:       time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+			expected: []string{"app.log"},
+		},
+		{
+			layout:   "%Z",
+			from:     time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+			to:       time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+			expected: []string{"UTC"},
+		},
+		{
+			layout: "%F",
+			from:   time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+			to:     time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tt := range tests {
+		if actual := strftime.ExpandAll(tt.layout, tt.from, tt.to); !reflect.DeepEqual(actual, tt.expected) {
+			t.Errorf("ExpandAll(%q, %v, %v): expected: %q; actual: %q", tt.layout, tt.from, tt.to, tt.expected, actual)
+		}
+	}
+}
+
+// TestExpandAllFarFuture checks times past the end of the transition
+// table, where daylight saving time follows a rule instead.
+func TestExpandAllFarFuture(t *testing.T) {
+	london, err := time.LoadLocation("Europe/London")
+	if err != nil {
+		t.Skip("time zone database unavailable:", err)
+	}
+	from := time.Date(2040, 6, 1, 0, 0, 0, 0, london)
+	to := from.AddDate(2, 0, 0)
+	var expected []string
+	for m := from; m.Before(to); m = m.AddDate(0, 1, 0) {
+		expected = append(expected, m.Format("2006/01"))
+	}
+
+	var actual []string
+	if !within(5*time.Second, func() { actual = strftime.ExpandAll("%Y/%m", from, to) }) {
+		t.Fatalf("ExpandAll(%q, %v, %v) did not return", "%Y/%m", from, to)
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("ExpandAll(%q, %v, %v): expected: %q; actual: %q", "%Y/%m", from, to, expected, actual)
+	}
+}
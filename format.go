@@ -7,6 +7,8 @@ package strftime
 
 import (
 	"time"
+	"unicode"
+	"unicode/utf8"
 )
 
 const (
@@ -32,6 +34,8 @@ const (
 	stdZeroMinute                                         // "04"
 	stdSecond                                             // "5"
 	stdZeroSecond                                         // "05"
+	stdUnderHour                                          // "_15"
+	stdUnderHour12                                        // "_3"
 	stdLongYear            = iota + stdNeedDate           // "2006"
 	stdYear                                               // "06"
 	stdFirstTwoDigitYear                                  // "20"
@@ -45,13 +49,63 @@ const (
 	stdNumTZ                                              // "-0700"  // always numeric
 	stdFracSecond0                                        // ".0", ".00", ... , trailing zeros included
 	stdFracSecond9                                        // ".9", ".99", ..., trailing zeros omitted
+	stdComposite                                          // expands to compositeLayout(loc, arg, nested)
+	stdUnix                                               // seconds since the Unix epoch
+	stdUnixMilli                                          // milliseconds since the Unix epoch
+	stdISO8601TZ                                          // "Z0700"  // prints Z for UTC
+	stdUnknown                                            // unknown or dangling specifier, copied through
 	stdNeedDate            = 1 << 8                       // need month, day, year
 	stdNeedClock           = 2 << 8                       // need hour, minute, second
 	stdNeedISOISO8601Week  = 4 << 8                       // need ISO8601 week and year
 	stdArgShift            = 16                           // extra argument in high bits, above low stdArgShift
 	stdMask                = 1<<stdArgShift - 1           // mask out argument
+	stdPadShift            = stdArgShift                  // GNU padding flag, one of stdPad*
+	stdCaseShift           = stdArgShift + 2              // GNU case flag, one of stdCase*
+	stdWidth               = 1 << (stdArgShift + 4)       // numeric argument is an explicit field width
+	stdValueShift          = stdArgShift + 5              // numeric argument: width, precision or composite index
+	stdFlagsMask           = 0x1F << stdArgShift          // mask out GNU flags and stdWidth
+	stdMaxWidth            = 1<<(31-stdValueShift) - 1    // widest field width, keeps std within 32 bits
 )
 
+// GNU flags, as recorded in the argument bits of a std value.
+const (
+	stdPadNone   = 1 // "-": do not pad a numeric result
+	stdPadSpace  = 2 // "_": pad a numeric result with spaces
+	stdPadZero   = 3 // "0": pad a numeric result with zeros
+	stdCaseUpper = 1 // "^": convert alphabetic characters to upper case
+	stdCaseSwap  = 2 // "#": use the opposite case
+)
+
+// Composite specifiers expand to a layout of their own, chosen by the
+// argument of stdComposite.
+const (
+	compositeDateTime   = iota // %c
+	compositeDate              // %x
+	compositeTime              // %X
+	compositeTime12            // %r
+	compositeShortDate         // %D
+	compositeISODate           // %F
+	compositeHourMinute        // %R
+	compositeClock             // %T
+	compositeNewline           // %n
+	compositeTab               // %t
+)
+
+// compositeLayouts holds the expansions of the composite specifiers
+// in the "C" locale.
+var compositeLayouts = [...]string{
+	compositeDateTime:   "%a %b %e %H:%M:%S %Y",
+	compositeDate:       "%m/%d/%Y",
+	compositeTime:       "%H:%M:%S",
+	compositeTime12:     "%I:%M:%S %p",
+	compositeShortDate:  "%m/%d/%y",
+	compositeISODate:    "%Y-%m-%d",
+	compositeHourMinute: "%H:%M",
+	compositeClock:      "%H:%M:%S",
+	compositeNewline:    "\n",
+	compositeTab:        "\t",
+}
+
 // Format returns a textual representation of the time value formatted
 // according to C99-compatible strftime layout.
 //
@@ -60,7 +114,7 @@ const (
 //  %A  full weekday name (Sunday)
 //  %b  abbreviated month name (Sep)
 //  %B  full month name (September)
-//  %c  the same as time.ANSIC (%a %b %e %H:%M:%S %)
+//  %c  date and time of the locale; %a %b %e %H:%M:%S %Y in the "C" locale
 //  %C  (year / 100) as number. Single digits are preceded by zero (20)
 //  %d  day of month as number. Single digits are preceded by zero (21)
 //  %D  equivalent to %m/%d/%y (09/21/14)
@@ -73,13 +127,18 @@ const (
 //  %H  the hour (24 hour clock) as a number. Single digits are preceded by zero (15)
 //  %I  the hour (12 hour clock) as a number. Single digits are preceded by zero (03)
 //  %j  the day of the year as a decimal number. Single digits are preced by zeros (264)
+//  %k  the hour (24 hour clock) as a number. Single digits are preceded by a blank ( 9)
+//  %l  the hour (12 hour clock) as a number. Single digits are preceded by a blank ( 3)
 //  %m  the month as a decimal number. Single digits are preceded by a zero (09)
 //  %M  the minute as a decimal number. Single digits are preceded by a zero (32)
 //  %n  a newline (\n)
+//  %N  nanoseconds as a nine digit decimal number. %3N gives milliseconds, %6N microseconds
 //  %p  AM or PM as appropriate
 //  %P  am or pm as appropriate
-//  %r  equivalent to %I:%M:%S %p
+//  %Q  milliseconds since the Unix epoch (1531142055000)
+//  %r  12-hour time of the locale; %I:%M:%S %p in the "C" locale
 //  %R  equivalent to %H:%M
+//  %s  seconds since the Unix epoch (1531142055)
 //  %S  the second as a number. Single digits are preceded by a zero (05)
 //  %t  a tab (\t)
 //  %T  equivalent to %H:%M:%S
@@ -88,12 +147,36 @@ const (
 //  %V  ISO 8601 week of the year
 //  %w  the weekday (Sunday as first day of the week) as a number. (0)
 //  %W  week of the year as a decimal number (Monday is the first day of the week)
-//  %x  equivalent to %m/%d/%Y
-//  %X  equivalent to %H:%M:%S
+//  %x  date of the locale; %m/%d/%Y in the "C" locale
+//  %X  time of the locale; %H:%M:%S in the "C" locale
 //  %y  year without century as a number. Single digits are preceded by zero (14)
 //  %Y  the year with century as a number (2014)
 //  %z  the time zone offset from UTC (-0700)
+//  %:z  the time zone offset with a colon (-07:00)
+//  %::z  the time zone offset with seconds (-07:00:00)
+//  %:::z  the time zone offset with only the precision needed (-07, +05:30)
 //  %Z  time zone name (UTC)
+//
+// As in GNU strftime, the following flags may appear between the '%'
+// and the specifier:
+//  -  do not pad a numeric result (%-d)
+//  _  pad a numeric result with spaces (%_H)
+//  0  pad a numeric result with zeros (%0e)
+//  ^  convert alphabetic characters in the result to upper case (%^a)
+//  #  use the opposite case (%#Z), or Z for a zero offset (%#:z)
+//
+// A decimal field width may follow the flags. Numeric results are padded
+// to the width with zeros, or with spaces for %e and the "_" and "-"
+// flags; textual results are padded with spaces, or with zeros for the
+// "0" flag (%10A, %4d). For %f the width is the number of digits (%3f).
+// The offsets of %z take the "#" flag only and are never padded, and
+// composite specifiers such as %c and %T ignore flags and widths.
+//
+// Format uses the "C" locale; FormatLocale expands %c, %x, %X and %r
+// from the Locale given.
+//
+// Unknown specifiers and a trailing '%' are copied through as is; use
+// FormatStrict or Validate to report them instead.
 func Format(t time.Time, layout string) string {
 	const bufSize = 64
 	var b [bufSize]byte
@@ -104,20 +187,12 @@ func Format(t time.Time, layout string) string {
 // AppendFormat is like Format but appends the textual
 // representation to b and returns the extended buffer.
 func AppendFormat(b []byte, t time.Time, layout string) []byte {
-	var (
-		name, offset, abs = locabs(&t)
-
-		year            = -1
-		month           time.Month
-		day             int
-		yday            int
-		hour            = -1
-		min             int
-		sec             int
-		iso8601WeekYear = -1
-		iso8601Week     int
-	)
+	return AppendFormatLocale(b, t, layout, nil)
+}
 
+// appendLayout appends the textual representation of layout to b and
+// returns the extended buffer.
+func (f *fields) appendLayout(b []byte, layout string) []byte {
 	// Each iteration generates one std value.
 	for layout != "" {
 		prefix, std, suffix := nextStdChunk(layout)
@@ -127,235 +202,549 @@ func AppendFormat(b []byte, t time.Time, layout string) []byte {
 		if std == 0 {
 			break
 		}
+		if std == stdUnknown {
+			if fn, n := f.custom(layout[len(prefix)+1:]); fn != nil {
+				b = appendFunc(b, fn, f.t)
+				layout = layout[len(prefix)+1+n:]
+				continue
+			}
+			b = f.appendUnknown(b, layout[len(prefix):len(layout)-len(suffix)])
+			layout = suffix
+			continue
+		}
 		layout = suffix
 
-		// Compute year, month, day if needed.
-		if year < 0 && std&stdNeedDate != 0 {
-			year, month, day, yday = absDate(abs, true)
+		if std&stdMask == stdComposite {
+			// Expanding in place instead of splicing the expansion into
+			// the rest of the layout keeps composites allocation-free.
+			nested := f.nested
+			f.nested = true
+			b = f.appendLayout(b, compositeLayout(f.loc, std>>stdValueShift, nested))
+			f.nested = nested
+			continue
 		}
+		f.compute(std)
+		b = f.appendStd(b, std)
+	}
+	return b
+}
 
-		// Compute hour, minute, second if needed.
-		if hour < 0 && std&stdNeedClock != 0 {
-			hour, min, sec = absClock(abs)
-		}
+// fields holds a time value together with its calendar fields,
+// which are computed lazily, only once some std value needs them.
+type fields struct {
+	t      time.Time
+	loc    *Locale  // nil for the built-in English names
+	opts   *Options // nil to copy unknown specifiers through
+	nested bool     // expanding a composite specifier
+	name   string
+	offset int
+	abs    uint64
 
-		// Compute ISO8601 week year if needed
-		if iso8601WeekYear < 0 && std&stdNeedISOISO8601Week != 0 {
-			iso8601WeekYear, iso8601Week = t.ISOWeek()
-		}
+	year    int
+	month   time.Month
+	day     int
+	yday    int
+	hour    int
+	min     int
+	sec     int
+	isoYear int
+	isoWeek int
+}
+
+func (f *fields) init(t time.Time, loc *Locale) {
+	f.t = t
+	f.loc = loc
+	f.name, f.offset, f.abs = locabs(&t)
+	f.year = -1
+	f.hour = -1
+	f.isoYear = -1
+}
 
+// compute computes the calendar fields requested by the stdNeed* bits
+// of need, unless they are already known.
+func (f *fields) compute(need int) {
+	// Compute year, month, day if needed.
+	if f.year < 0 && need&stdNeedDate != 0 {
+		f.year, f.month, f.day, f.yday = absDate(f.abs, true)
+	}
+
+	// Compute hour, minute, second if needed.
+	if f.hour < 0 && need&stdNeedClock != 0 {
+		f.hour, f.min, f.sec = absClock(f.abs)
+	}
+
+	// Compute ISO8601 week year if needed
+	if f.isoYear < 0 && need&stdNeedISOISO8601Week != 0 {
+		f.isoYear, f.isoWeek = f.t.ISOWeek()
+	}
+}
+
+// appendStd appends the textual representation of a single std value
+// to b and returns the extended buffer. The fields std needs must
+// already have been computed.
+func (f *fields) appendStd(b []byte, std int) []byte {
+	if std&stdFlagsMask != 0 {
+		return f.appendFlagged(b, std)
+	}
+	return f.appendValue(b, std)
+}
+
+// appendValue is like appendStd for a std value without GNU flags or
+// field width. Keeping it apart from appendFlagged avoids a recursion
+// that would make escape analysis move the caller's buffer to the heap.
+func (f *fields) appendValue(b []byte, std int) []byte {
+	switch std & stdMask {
+	case stdISO8601WeekYear:
+		b = appendInt(b, f.isoYear%100, 2)
+	case stdISO8601LongWeekYear:
+		b = appendInt(b, f.isoYear, 4)
+	case stdISO8601Week:
+		b = appendInt(b, f.isoWeek, 2)
+	case stdYear:
+		y := f.year
+		if y < 0 {
+			y = -y
+		}
+		b = appendInt(b, y%100, 2)
+	case stdLongYear:
+		b = appendInt(b, f.year, 4)
+	case stdFirstTwoDigitYear:
+		b = appendInt(b, f.year/100, 2)
+	case stdYearDay:
+		b = appendInt(b, f.yday+1, 3)
+	case stdMonth:
+		if f.loc != nil {
+			b = append(b, f.loc.ShortMonthNames[f.month-1]...)
+			break
+		}
+		b = append(b, f.month.String()[:3]...)
+	case stdLongMonth:
+		if f.loc != nil {
+			b = append(b, f.loc.LongMonthNames[f.month-1]...)
+			break
+		}
+		m := f.month.String()
+		b = append(b, m...)
+	case stdNumMonth:
+		b = appendInt(b, int(f.month), 0)
+	case stdZeroMonth:
+		b = appendInt(b, int(f.month), 2)
+	case stdWeekDay:
+		if f.loc != nil {
+			b = append(b, f.loc.ShortDayNames[absWeekday(f.abs)]...)
+			break
+		}
+		b = append(b, absWeekday(f.abs).String()[:3]...)
+	case stdLongWeekDay:
+		if f.loc != nil {
+			b = append(b, f.loc.LongDayNames[absWeekday(f.abs)]...)
+			break
+		}
+		s := absWeekday(f.abs).String()
+		b = append(b, s...)
+	case stdZeroBasedNumWeekDay:
+		w := int(absWeekday(f.abs))
+		b = appendInt(b, w, 0)
+	case stdNumWeekDay:
+		w := int(absWeekday(f.abs))
+		if w == 0 {
+			w = 7
+		}
+		b = appendInt(b, w, 0)
+	case stdWeekOfYear, stdMonFirstWeekOfYear:
+		// Days since the first day of the week (Sunday for %U, Monday for %W).
+		w := (int(absWeekday(f.abs)) + 7 - (std&stdMask - stdWeekOfYear)) % 7
+		b = appendInt(b, (f.yday+7-w)/7, 2)
+	case stdDay:
+		b = appendInt(b, f.day, 0)
+	case stdUnderDay:
+		if f.day < 10 {
+			b = append(b, ' ')
+		}
+		b = appendInt(b, f.day, 0)
+	case stdZeroDay:
+		b = appendInt(b, f.day, 2)
+	case stdHour:
+		b = appendInt(b, f.hour, 2)
+	case stdUnderHour:
+		if f.hour < 10 {
+			b = append(b, ' ')
+		}
+		b = appendInt(b, f.hour, 0)
+	case stdHour12, stdZeroHour12, stdUnderHour12:
+		// Noon is 12PM, midnight is 12AM.
+		hr := f.hour % 12
+		if hr == 0 {
+			hr = 12
+		}
 		switch std & stdMask {
-		case stdNop:
-			continue
-		case stdISO8601WeekYear:
-			b = appendInt(b, iso8601WeekYear%100, 2)
-		case stdISO8601LongWeekYear:
-			b = appendInt(b, iso8601WeekYear, 4)
-		case stdISO8601Week:
-			b = appendInt(b, iso8601Week, 2)
-		case stdYear:
-			y := year
-			if y < 0 {
-				y = -y
-			}
-			b = appendInt(b, y%100, 2)
-		case stdLongYear:
-			b = appendInt(b, year, 4)
-		case stdFirstTwoDigitYear:
-			b = appendInt(b, year/100, 2)
-		case stdYearDay:
-			b = appendInt(b, yday+1, 3)
-		case stdMonth:
-			b = append(b, month.String()[:3]...)
-		case stdLongMonth:
-			m := month.String()
-			b = append(b, m...)
-		//case stdNumMonth:
-		//	b = appendInt(b, int(month), 0)
-		case stdZeroMonth:
-			b = appendInt(b, int(month), 2)
-		case stdWeekDay:
-			b = append(b, absWeekday(abs).String()[:3]...)
-		case stdLongWeekDay:
-			s := absWeekday(abs).String()
-			b = append(b, s...)
-		case stdZeroBasedNumWeekDay:
-			w := int(absWeekday(abs))
-			b = appendInt(b, w, 0)
-		case stdNumWeekDay:
-			w := int(absWeekday(abs))
-			if w == 0 {
-				w = 7
-			}
-			b = appendInt(b, w, 0)
-		case stdWeekOfYear, stdMonFirstWeekOfYear:
-			w := int(absWeekday(abs))
-			n := w - (std - stdWeekOfYear)
-			if n < 0 {
-				n = 7
-			}
-			n = ((yday - n) / 7) + 1
-			b = appendInt(b, n, 2)
-		//case stdDay:
-		//	b = appendInt(b, day, 0)
-		case stdUnderDay:
-			if day < 10 {
-				b = append(b, ' ')
-			}
-			b = appendInt(b, day, 0)
-		case stdZeroDay:
-			b = appendInt(b, day, 2)
-		case stdHour:
-			b = appendInt(b, hour, 2)
-		//case stdHour12:
-		//	// Noon is 12PM, midnight is 12AM.
-		//	hr := hour % 12
-		//	if hr == 0 {
-		//		hr = 12
-		//	}
-		//	b = appendInt(b, hr, 0)
+		case stdHour12:
+			b = appendInt(b, hr, 0)
 		case stdZeroHour12:
-			// Noon is 12PM, midnight is 12AM.
-			hr := hour % 12
-			if hr == 0 {
-				hr = 12
-			}
 			b = appendInt(b, hr, 2)
-		//case stdMinute:
-		//	b = appendInt(b, min, 0)
-		case stdZeroMinute:
-			b = appendInt(b, min, 2)
-		//case stdSecond:
-		//	b = appendInt(b, sec, 0)
-		case stdZeroSecond:
-			b = appendInt(b, sec, 2)
-		case stdPM:
-			if hour >= 12 {
-				b = append(b, "PM"...)
-			} else {
-				b = append(b, "AM"...)
-			}
-		case stdpm:
-			if hour >= 12 {
-				b = append(b, "pm"...)
-			} else {
-				b = append(b, "am"...)
-			}
-		case stdNumTZ:
-			zone := offset / 60 // convert to minutes
-			if zone < 0 {
-				b = append(b, '-')
-				zone = -zone
-			} else {
-				b = append(b, '+')
-			}
-			b = appendInt(b, zone/60, 2)
-			b = appendInt(b, zone%60, 2)
-		case stdTZ:
-			if name != "" {
-				b = append(b, name...)
-				break
+		default:
+			if hr < 10 {
+				b = append(b, ' ')
 			}
-		case stdFracSecond0, stdFracSecond9:
-			b = formatNano(b, uint(t.Nanosecond()), std>>stdArgShift, std&stdMask == stdFracSecond9)
+			b = appendInt(b, hr, 0)
+		}
+	case stdMinute:
+		b = appendInt(b, f.min, 0)
+	case stdZeroMinute:
+		b = appendInt(b, f.min, 2)
+	case stdSecond:
+		b = appendInt(b, f.sec, 0)
+	case stdZeroSecond:
+		b = appendInt(b, f.sec, 2)
+	case stdPM:
+		if f.loc != nil {
+			b = append(b, f.loc.dayPeriod(f.hour)...)
+			break
+		}
+		if f.hour >= 12 {
+			b = append(b, "PM"...)
+		} else {
+			b = append(b, "AM"...)
+		}
+	case stdpm:
+		if f.loc != nil {
+			b = appendLower(b, f.loc.dayPeriod(f.hour))
+			break
 		}
+		if f.hour >= 12 {
+			b = append(b, "pm"...)
+		} else {
+			b = append(b, "am"...)
+		}
+	case stdISO8601TZ, stdNumTZ:
+		if std&stdMask == stdISO8601TZ && f.offset == 0 {
+			b = append(b, 'Z')
+			break
+		}
+		b = appendOffset(b, f.offset, std>>stdValueShift)
+	case stdTZ:
+		if f.name != "" {
+			b = append(b, f.name...)
+			break
+		}
+	case stdFracSecond0, stdFracSecond9:
+		b = formatNano(b, uint(f.t.Nanosecond()), std>>stdValueShift, std&stdMask == stdFracSecond9)
+	case stdUnix:
+		b = appendInt64(b, f.t.Unix())
+	case stdUnixMilli:
+		// Unix rounds down and Nanosecond is never negative, so the sum
+		// rounds down too, even before the epoch.
+		b = appendInt64(b, f.t.Unix()*1e3+int64(f.t.Nanosecond()/1e6))
 	}
 	return b
 }
 
+// appendFlagged is like appendStd for a std value carrying GNU flags
+// or a field width. The value is formatted as usual and then padded or
+// case converted in place.
+func (f *fields) appendFlagged(b []byte, std int) []byte {
+	n := len(b)
+	b = f.appendValue(b, std&^stdFlagsMask)
+
+	width := 0
+	if std&stdWidth != 0 {
+		width = std >> stdValueShift
+	}
+	pad := std >> stdPadShift & 3
+	switch std & stdMask {
+	case stdpm:
+		// Like glibc, swapping the case of %P keeps it in lower case.
+		if std>>stdCaseShift&3 == stdCaseUpper {
+			b = convertCase(b, n, stdCaseUpper)
+		}
+		b = padText(b, n, width, pad)
+	case stdMonth, stdLongMonth, stdWeekDay, stdLongWeekDay, stdPM, stdTZ:
+		if cas := std >> stdCaseShift & 3; cas != 0 {
+			b = convertCase(b, n, cas)
+		}
+		b = padText(b, n, width, pad)
+	case stdNumTZ, stdFracSecond0, stdFracSecond9:
+		// Neither padded nor alphabetic.
+	default:
+		switch {
+		case pad == 0:
+			pad = stdDefaultPad(std)
+		case pad == stdPadNone && width > 0:
+			// Like glibc, "-" with a width pads with spaces.
+			pad = stdPadSpace
+		}
+		if width < len(b)-n {
+			width = len(b) - n
+		}
+		b = repad(b, n, width, pad)
+	}
+	return b
+}
+
+// stdDefaultPad returns how the numeric std value is padded when widened,
+// one of stdPad*.
+func stdDefaultPad(std int) int {
+	switch std & stdMask {
+	case stdUnderDay, stdUnderHour, stdUnderHour12:
+		return stdPadSpace
+	}
+	return stdPadZero
+}
+
 // nextStdChunk finds the first occurrence of a std string in
 // layout and returns the text before, the std string, and the text after.
+//
+// A std string is a '%', optionally followed by GNU flags, and the
+// conversion character. The flags are recorded in the argument bits of std.
+// An unknown conversion character, or a '%' at the end of layout, yields
+// stdUnknown with the offending text as the std string.
 func nextStdChunk(layout string) (prefix string, std int, suffix string) {
 	for i := 0; i < len(layout); i++ {
-		j := i + 1
-		if int(layout[i]) == '%' && len(layout) > j {
-			spec := int(layout[j])
-			switch spec {
-			case 'a': // Mon
-				return layout[0:i], stdWeekDay, layout[j+1:]
-			case 'A': // Monday
-				return layout[0:i], stdLongWeekDay, layout[j+1:]
-			case 'b', 'h': // Jan
-				return layout[0:i], stdMonth, layout[j+1:]
-			case 'B': // January
-				return layout[0:i], stdLongMonth, layout[j+1:]
-			case 'c': // "Mon Jan _2 15:04:05 2006" (assumes "C" locale)
-				return layout[0:i], stdNop, "%a %b %e %H:%M:%S %Y" + layout[j+1:]
-			case 'C': // 20
-				return layout[0:i], stdFirstTwoDigitYear, layout[j+1:]
-			case 'd': // 02
-				return layout[0:i], stdZeroDay, layout[j+1:]
-			case 'D': // %m/%d/%y
-				return layout[0:i], stdNop, "%m/%d/%y" + layout[j+1:]
-			case 'e': // _2
-				return layout[0:i], stdUnderDay, layout[j+1:]
-			case 'f': // fraction seconds in microseconds (Python)
-				std = stdFracSecond0
-				std |= 6 << stdArgShift // microseconds precision
-				return layout[0:i], std, layout[j+1:]
-			case 'F': // %Y-%m-%d
-				return layout[0:i], stdNop, "%Y-%m-%d" + layout[j+1:]
-			case 'g':
-				return layout[0:i], stdISO8601WeekYear, layout[j+1:]
-			case 'G':
-				return layout[0:i], stdISO8601LongWeekYear, layout[j+1:]
-			case 'H':
-				return layout[0:i], stdHour, layout[j+1:]
-			case 'I':
-				return layout[0:i], stdZeroHour12, layout[j+1:]
-			case 'j':
-				return layout[0:i], stdYearDay, layout[j+1:]
-			case 'm':
-				return layout[0:i], stdZeroMonth, layout[j+1:]
-			case 'M':
-				return layout[0:i], stdZeroMinute, layout[j+1:]
-			case 'n':
-				return layout[0:i] + "\n", stdNop, layout[j+1:]
-			case 'p':
-				return layout[0:i], stdPM, layout[j+1:]
-			case 'P':
-				return layout[0:i], stdpm, layout[j+1:]
-			case 'r':
-				return layout[0:i], stdNop, "%I:%M:%S %p" + layout[j+1:]
-			case 'R': // %H:%M"
-				return layout[0:i], stdNop, "%H:%M" + layout[j+1:]
-			case 'S':
-				return layout[0:i], stdZeroSecond, layout[j+1:]
-			case 't':
-				return layout[0:i] + "\t", stdNop, layout[j+1:]
-			case 'T': // %H:%M:%S
-				return layout[0:i], stdNop, "%H:%M:%S" + layout[j+1:]
-			case 'u': // weekday as a decimal number, where Monday is 1
-				return layout[0:i], stdNumWeekDay, layout[j+1:]
-			case 'U': // week of the year as a decimal number (Sunday is the first day of the week)
-				return layout[0:i], stdWeekOfYear, layout[j+1:]
-			case 'V':
-				return layout[0:i], stdISO8601Week, layout[j+1:]
-			case 'w':
-				return layout[0:i], stdZeroBasedNumWeekDay, layout[j+1:]
-			case 'W': // week of the year as a decimal number (Monday is the first day of the week)
-				return layout[0:i], stdMonFirstWeekOfYear, layout[j+1:]
-			case 'x': // locale depended date representation (assumes "C" locale)
-				return layout[0:i], stdNop, "%m/%d/%Y" + layout[j+1:]
-			case 'X': // locale depended time representation (assumes "C" locale)
-				return layout[0:i], stdNop, "%H:%M:%S" + layout[j+1:]
-			case 'y':
-				return layout[0:i], stdYear, layout[j+1:]
-			case 'Y':
-				return layout[0:i], stdLongYear, layout[j+1:]
-			case 'z':
-				return layout[0:i], stdNumTZ, layout[j+1:]
-			case 'Z':
-				return layout[0:i], stdTZ, layout[j+1:]
-			case '%':
-				return layout[0:i] + "%", stdNop, layout[j+1:]
-			}
+		if layout[i] != '%' {
+			continue
 		}
+		j, flags := stdFlags(layout, i+1)
+		colons := 0
+		for colons < 3 && j < len(layout) && layout[j] == ':' {
+			colons++
+			j++
+		}
+		if j >= len(layout) {
+			// A dangling '%', possibly followed by flags.
+			return layout[0:i], stdUnknown, ""
+		}
+		if colons > 0 && layout[j] != 'z' {
+			return layout[0:i], stdUnknown, layout[unknownEnd(layout, j):]
+		}
+		switch layout[j] {
+		case 'a': // Mon
+			std = stdWeekDay
+		case 'A': // Monday
+			std = stdLongWeekDay
+		case 'b', 'h': // Jan
+			std = stdMonth
+		case 'B': // January
+			std = stdLongMonth
+		case 'c': // locale's date and time, "Mon Jan _2 15:04:05 2006" in the "C" locale
+			std = stdComposite | compositeDateTime<<stdValueShift
+		case 'C': // 20
+			std = stdFirstTwoDigitYear
+		case 'd': // 02
+			std = stdZeroDay
+		case 'D': // %m/%d/%y
+			std = stdComposite | compositeShortDate<<stdValueShift
+		case 'e': // _2
+			std = stdUnderDay
+		case 'f': // fraction seconds in microseconds (Python)
+			std = stdFracSecond0
+			std |= 6 << stdValueShift // microseconds precision
+		case 'F': // %Y-%m-%d
+			std = stdComposite | compositeISODate<<stdValueShift
+		case 'g':
+			std = stdISO8601WeekYear
+		case 'G':
+			std = stdISO8601LongWeekYear
+		case 'H':
+			std = stdHour
+		case 'I':
+			std = stdZeroHour12
+		case 'j':
+			std = stdYearDay
+		case 'k': // hour (24 hour clock), blank padded
+			std = stdUnderHour
+		case 'l': // hour (12 hour clock), blank padded
+			std = stdUnderHour12
+		case 'm':
+			std = stdZeroMonth
+		case 'M':
+			std = stdZeroMinute
+		case 'N': // fraction seconds in nanoseconds (GNU), %3N for milliseconds
+			std = stdFracSecond0
+			std |= 9 << stdValueShift // nanoseconds precision
+		case 'n':
+			std = stdComposite | compositeNewline<<stdValueShift
+		case 'p':
+			std = stdPM
+		case 'P':
+			std = stdpm
+		case 'Q': // milliseconds since the epoch (Ruby)
+			std = stdUnixMilli
+		case 'r': // locale's 12-hour time, %I:%M:%S %p in the "C" locale
+			std = stdComposite | compositeTime12<<stdValueShift
+		case 'R': // %H:%M"
+			std = stdComposite | compositeHourMinute<<stdValueShift
+		case 's': // seconds since the epoch
+			std = stdUnix
+		case 'S':
+			std = stdZeroSecond
+		case 't':
+			std = stdComposite | compositeTab<<stdValueShift
+		case 'T': // %H:%M:%S
+			std = stdComposite | compositeClock<<stdValueShift
+		case 'u': // weekday as a decimal number, where Monday is 1
+			std = stdNumWeekDay
+		case 'U': // week of the year as a decimal number (Sunday is the first day of the week)
+			std = stdWeekOfYear
+		case 'V':
+			std = stdISO8601Week
+		case 'w':
+			std = stdZeroBasedNumWeekDay
+		case 'W': // week of the year as a decimal number (Monday is the first day of the week)
+			std = stdMonFirstWeekOfYear
+		case 'x': // locale's date, %m/%d/%Y in the "C" locale
+			std = stdComposite | compositeDate<<stdValueShift
+		case 'X': // locale's time, %H:%M:%S in the "C" locale
+			std = stdComposite | compositeTime<<stdValueShift
+		case 'y':
+			std = stdYear
+		case 'Y':
+			std = stdLongYear
+		case 'z': // -0700, %:z -07:00, %::z -07:00:00, %:::z -07
+			std = stdNumTZ | colons<<stdValueShift
+		case 'Z':
+			std = stdTZ
+		case '%':
+			return layout[0 : i+1], stdNop, layout[j+1:] // keep the first '%' as literal text
+		}
+		if std != 0 {
+			return layout[0:i], stdWithFlags(std, flags), layout[j+1:]
+		}
+		return layout[0:i], stdUnknown, layout[unknownEnd(layout, j):]
 	}
 
 	return layout, 0, ""
 }
 
+// unknownEnd returns the index just past the unknown conversion character
+// at layout[j]. A '%' is left alone as it starts the next specifier, and a
+// multi-byte character is kept whole.
+func unknownEnd(layout string, j int) int {
+	switch c := layout[j]; {
+	case c == '%':
+		return j
+	case c >= utf8.RuneSelf:
+		_, size := utf8.DecodeRuneInString(layout[j:])
+		return j + size
+	}
+	return j + 1
+}
+
+// stdFlags parses the GNU flags and the optional field width starting at
+// layout[i] and returns the index of the first byte after them along with
+// the flags, encoded as in the argument bits of a std value. When a flag is
+// repeated, the last one wins. Widths above stdMaxWidth are clamped.
+func stdFlags(layout string, i int) (int, int) {
+	pad, cas := 0, 0
+flags:
+	for ; i < len(layout); i++ {
+		switch layout[i] {
+		case '-':
+			pad = stdPadNone
+		case '_':
+			pad = stdPadSpace
+		case '0':
+			pad = stdPadZero
+		case '^':
+			cas = stdCaseUpper
+		case '#':
+			cas = stdCaseSwap
+		default:
+			break flags
+		}
+	}
+	flags := pad<<stdPadShift | cas<<stdCaseShift
+
+	width, n := 0, 0
+	for ; i < len(layout) && isDigit(layout[i]); i++ {
+		if width = width*10 + int(layout[i]-'0'); width > stdMaxWidth {
+			width = stdMaxWidth
+		}
+		n++
+	}
+	if n > 0 {
+		flags |= stdWidth | width<<stdValueShift
+	}
+	return i, flags
+}
+
+// stdWithFlags applies the GNU flags and field width to std. The unpadded
+// month, day, hour, minute and second have std values of their own, as in
+// the time package. For fractional seconds the width is the number of
+// digits; composites ignore both flags and width.
+func stdWithFlags(std, flags int) int {
+	switch std & stdMask {
+	case stdComposite:
+		return std
+	case stdNumTZ:
+		// The numeric argument holds the number of colons; "#" selects
+		// Z for UTC. Neither padding nor width applies.
+		if flags>>stdCaseShift&3 == stdCaseSwap {
+			return std&^stdMask | stdISO8601TZ
+		}
+		return std
+	case stdFracSecond0, stdFracSecond9:
+		if flags&stdWidth != 0 {
+			return std&stdMask | flags>>stdValueShift<<stdValueShift
+		}
+		return std
+	case stdZeroMonth, stdZeroDay, stdZeroHour12, stdZeroMinute, stdZeroSecond:
+		if flags>>stdPadShift&3 == stdPadNone && flags&stdWidth == 0 {
+			flags &^= 3 << stdPadShift
+			std = unpaddedStd(std)
+		}
+	}
+	return std | flags
+}
+
+// unpaddedStd returns the unpadded counterpart of a zero-padded std value.
+func unpaddedStd(std int) int {
+	switch std {
+	case stdZeroMonth:
+		return stdNumMonth
+	case stdZeroDay:
+		return stdDay
+	case stdZeroHour12:
+		return stdHour12
+	case stdZeroMinute:
+		return stdMinute
+	case stdZeroSecond:
+		return stdSecond
+	}
+	return std
+}
+
+// appendOffset appends the zone offset, in seconds east of UTC, as
+// "-0700". With colons set to 1 or 2 it appends "-07:00" or "-07:00:00";
+// with 3, the shortest of "-07", "-07:00" and "-07:00:00" that is exact.
+func appendOffset(b []byte, offset int, colons int) []byte {
+	if offset < 0 {
+		b = append(b, '-')
+		offset = -offset
+	} else {
+		b = append(b, '+')
+	}
+	hh, mm, ss := offset/3600, offset/60%60, offset%60
+	b = appendInt(b, hh, 2)
+	switch colons {
+	case 0:
+		return appendInt(b, mm, 2)
+	case 3:
+		if mm == 0 && ss == 0 {
+			return b
+		}
+		if ss == 0 {
+			colons = 1
+		}
+	}
+	b = append(b, ':')
+	b = appendInt(b, mm, 2)
+	if colons == 1 {
+		return b
+	}
+	b = append(b, ':')
+	return appendInt(b, ss, 2)
+}
+
 // appendInt appends the decimal form of x to b and returns the result.
 // If the decimal form (excluding sign) is shorter than width, the result is padded with leading 0's.
 // Duplicates functionality in strconv, but avoids dependency.
@@ -387,6 +776,131 @@ func appendInt(b []byte, x int, width int) []byte {
 	return append(b, buf[i:]...)
 }
 
+// padText pads the text in b[n:] on the left to width characters, with
+// zeros for stdPadZero and with spaces otherwise.
+func padText(b []byte, n int, width int, pad int) []byte {
+	count := width - utf8.RuneCount(b[n:])
+	if count <= 0 {
+		return b
+	}
+	c := byte(' ')
+	if pad == stdPadZero {
+		c = '0'
+	}
+	end := len(b)
+	for i := 0; i < count; i++ {
+		b = append(b, c)
+	}
+	copy(b[n+count:], b[n:end])
+	for i := n; i < n+count; i++ {
+		b[i] = c
+	}
+	return b
+}
+
+// repad replaces the padding of the number in b[n:] according to pad,
+// one of stdPad*, padding it to width.
+func repad(b []byte, n int, width int, pad int) []byte {
+	num := b[n:]
+	neg := len(num) > 0 && num[0] == '-'
+	if neg {
+		num = num[1:]
+	}
+	// Strip the existing padding, keeping at least one digit.
+	for len(num) > 1 && (num[0] == '0' || num[0] == ' ') {
+		num = num[1:]
+	}
+
+	var buf [20]byte
+	digits := buf[:copy(buf[:], num)]
+	b = b[:n]
+	w := len(digits)
+	if neg {
+		w++
+	}
+	if pad == stdPadSpace {
+		for ; w < width; w++ {
+			b = append(b, ' ')
+		}
+	}
+	if neg {
+		b = append(b, '-')
+	}
+	if pad == stdPadZero {
+		for ; w < width; w++ {
+			b = append(b, '0')
+		}
+	}
+	return append(b, digits...)
+}
+
+// convertCase converts the text in b[n:] according to cas, one of
+// stdCase*. Swapping the case converts text to upper case unless it has
+// no lower case letters, in which case it is converted to lower case.
+func convertCase(b []byte, n int, cas int) []byte {
+	upper := cas == stdCaseUpper
+	if !upper {
+		for _, r := range string(b[n:]) {
+			if unicode.IsLower(r) {
+				upper = true
+				break
+			}
+		}
+	}
+
+	// Converted runes may change in size, so append the converted text
+	// and move it over the original.
+	end := len(b)
+	for i := n; i < end; {
+		c := b[i]
+		if c < utf8.RuneSelf {
+			if upper && 'a' <= c && c <= 'z' {
+				c -= 'a' - 'A'
+			} else if !upper && 'A' <= c && c <= 'Z' {
+				c += 'a' - 'A'
+			}
+			b = append(b, c)
+			i++
+			continue
+		}
+		r, size := utf8.DecodeRune(b[i:end])
+		if upper {
+			r = unicode.ToUpper(r)
+		} else {
+			r = unicode.ToLower(r)
+		}
+		var buf [utf8.UTFMax]byte
+		b = append(b, buf[:utf8.EncodeRune(buf[:], r)]...)
+		i += size
+	}
+	return b[:n+copy(b[n:], b[end:])]
+}
+
+// appendInt64 appends the decimal form of x to b and returns the result.
+// Unlike appendInt, it does not overflow on 32-bit platforms for the
+// values of stdUnix and stdUnixMilli.
+func appendInt64(b []byte, x int64) []byte {
+	u := uint64(x)
+	if x < 0 {
+		b = append(b, '-')
+		u = uint64(-x)
+	}
+
+	// Assemble decimal in reverse order.
+	var buf [20]byte
+	i := len(buf)
+	for u >= 10 {
+		i--
+		q := u / 10
+		buf[i] = byte('0' + u - q*10)
+		u = q
+	}
+	i--
+	buf[i] = byte('0' + u)
+
+	return append(b, buf[i:]...)
+}
+
 // formatNano appends a fractional second, as nanoseconds, to b
 // and returns the result.
 // Duplicated from the standard Go library.
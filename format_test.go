@@ -22,6 +22,7 @@
 package strftime_test
 
 import (
+	"strings"
 	"testing"
 	"time"
 
@@ -41,7 +42,7 @@ var (
 	}{
 		{time: t1, layout: "%", expected: "%"},
 		{time: t1, layout: "%%", expected: "%"},
-		{time: t1, layout: "%Q", expected: "%Q"},
+		{time: t1, layout: "%J", expected: "%J"},
 		{time: t1, layout: "%%n", expected: "%n"},
 		{time: t1, layout: "%%t", expected: "%t"},
 		{time: t1, layout: "%n%t", expected: "\n\t"},
@@ -94,7 +95,77 @@ var (
 		{time: t1, layout: "bar%", expected: "bar%"},
 		{time: t1, layout: "%1", expected: "%1"},
 		{time: t1, layout: "%U %W", expected: "27 28"},
+		{time: t2, layout: "%U %W", expected: "50 49"},
+		{time: t3, layout: "%U %W", expected: "00 00"},
+		{time: t4, layout: "%U %W", expected: "00 00"},
 		{time: t1, layout: "%Y-%m-%dtest\n\t%Z", expected: "2018-07-09test\n\tUTC"},
+		{time: t1, layout: "%-d", expected: "9"},
+		{time: t2, layout: "%-d", expected: "10"},
+		{time: t1, layout: "%_H", expected: "13"},
+		{time: t2, layout: "%_H", expected: " 4"},
+		{time: t1, layout: "%0e", expected: "09"},
+		{time: t1, layout: "%-e", expected: "9"},
+		{time: t1, layout: "%_d", expected: " 9"},
+		{time: t1, layout: "%_m", expected: " 7"},
+		{time: t1, layout: "%-m/%-d/%-y", expected: "7/9/18"},
+		{time: t1, layout: "%-I:%-M:%-S", expected: "1:14:15"},
+		{time: t2, layout: "%-I:%-M:%-S", expected: "4:45:59"},
+		{time: t2, layout: "%_I:%_M", expected: " 4:45"},
+		{time: t1, layout: "%-j %_j %-C %_y %-V %0u %_Y", expected: "190 190 20 18 28 1 2018"},
+		{time: t1, layout: "%-_d %_-d", expected: " 9 9"},
+		{time: t1, layout: "%^a %^A %^b %^h %^B", expected: "MON MONDAY JUL JUL JULY"},
+		{time: t1, layout: "%#a %#A %#b %#B", expected: "MON MONDAY JUL JULY"},
+		{time: t1, layout: "%^p %#p %^P %#P", expected: "PM pm PM pm"},
+		{time: t1, layout: "%^Z %#Z", expected: "UTC utc"},
+		{time: t1, layout: "%^#a %#^a", expected: "MON MON"},
+		{time: t1, layout: "%-", expected: "%-"},
+		{time: t1, layout: "%-J", expected: "%-J"},
+		{time: t1, layout: "%-%", expected: "%"},
+		{time: t1, layout: "%^c", expected: "Mon Jul  9 13:14:15 2018"},
+		{time: t1, layout: "[%10d]", expected: "[0000000009]"},
+		{time: t1, layout: "[%1d]", expected: "[09]"},
+		{time: t1, layout: "[%1Y]", expected: "[2018]"},
+		{time: t1, layout: "[%5G]", expected: "[02018]"},
+		{time: t1, layout: "[%10A]", expected: "[    Monday]"},
+		{time: t1, layout: "[%-10A]", expected: "[    Monday]"},
+		{time: t1, layout: "[%010A]", expected: "[0000Monday]"},
+		{time: t1, layout: "[%^10a]", expected: "[       MON]"},
+		{time: t1, layout: "[%^10B]", expected: "[      JULY]"},
+		{time: t1, layout: "[%10p]", expected: "[        PM]"},
+		{time: t1, layout: "[%5Z]", expected: "[  UTC]"},
+		{time: t1, layout: "[%4e]", expected: "[   9]"},
+		{time: t1, layout: "[%04e]", expected: "[0009]"},
+		{time: t1, layout: "[%_4d]", expected: "[   9]"},
+		{time: t1, layout: "[%-4d]", expected: "[   9]"},
+		{time: t1, layout: "[%-5m]", expected: "[    7]"},
+		{time: t1, layout: "[%05y]", expected: "[00018]"},
+		{time: t1, layout: "[%6C]", expected: "[000020]"},
+		{time: t1, layout: "[%3j] [%1j]", expected: "[190] [190]"},
+		{time: t1, layout: "[%5u] [%_5u]", expected: "[00001] [    1]"},
+		{time: t1, layout: "[%3U] [%5U] [%3W] [%_3W]", expected: "[027] [00027] [028] [ 28]"},
+		{time: time.Date(2018, time.January, 7, 0, 0, 0, 0, time.UTC), layout: "[%4U] [%4W]", expected: "[0001] [0001]"},
+		{time: t2, layout: "[%_3I] [%4M] [%-3S] [%4w]", expected: "[  4] [0045] [ 59] [0000]"},
+		{time: t1, layout: "[%10T]", expected: "[13:14:15]"},
+		{time: t1, layout: "[%10z] [%_10:z] [%^z]", expected: "[+0000] [+00:00] [+0000]"},
+		{time: t1, layout: "%3f|%9f", expected: "000|000000000"},
+		{time: t1, layout: "[%99999d]", expected: "[" + strings.Repeat("0", 1021) + "09]"},
+		{time: t1, layout: "%s|%Q", expected: "1531142055|1531142055000"},
+		{time: t2, layout: "%s|%Q", expected: "-601499641|-601499641000"},
+		{time: time.Unix(-1, 250000000), layout: "%s.%N|%Q", expected: "-1.250000000|-750"},
+		{time: time.Unix(1531142055, 123456789), layout: "%s.%N|%Q", expected: "1531142055.123456789|1531142055123"},
+		{time: time.Unix(1531142055, 123456789), layout: "%3N|%6N|%9N", expected: "123|123456|123456789"},
+		{time: t1, layout: "[%12s] [%_12s] [%-s]", expected: "[001531142055] [  1531142055] [1531142055]"},
+		{time: t1, layout: "%z|%:z|%::z|%:::z", expected: "+0000|+00:00|+00:00:00|+00"},
+		{time: t1, layout: "%#z|%#:z|%#::z|%#:::z", expected: "Z|Z|Z|Z"},
+		{time: t1.In(time.FixedZone("", 5*3600+30*60)), layout: "%:z|%::z|%:::z|%#:z", expected: "+05:30|+05:30:00|+05:30|+05:30"},
+		{time: t1.In(time.FixedZone("LMT", -(4*3600 + 56*60 + 2))), layout: "%z|%:z|%::z|%:::z", expected: "-0456|-04:56|-04:56:02|-04:56:02"},
+		{time: t1.In(time.FixedZone("", -7*3600)), layout: "%Y-%m-%dT%H:%M:%S%#:z", expected: "2018-07-09T06:14:15-07:00"},
+		{time: t1, layout: "%Y-%m-%dT%H:%M:%S%#:z", expected: "2018-07-09T13:14:15Z"},
+		{time: t1, layout: "%:Y|%::::z|%:", expected: "%:Y|%::::z|%:"},
+		{time: t1, layout: "%é|%:%Y|%-", expected: "%é|%:2018|%-"},
+		{time: t1, layout: "[%k] [%l]", expected: "[13] [ 1]"},
+		{time: t2, layout: "[%k] [%l] [%-k] [%0l] [%3k] [%-3l]", expected: "[ 4] [ 4] [4] [04] [  4] [  4]"},
+		{time: time.Date(2018, time.July, 9, 0, 5, 0, 0, time.UTC), layout: "%l:%M %p|%k:%M", expected: "12:05 AM| 0:05"},
 	}
 )
 
@@ -110,3 +181,47 @@ func TestFormat(t *testing.T) {
 		})
 	}
 }
+
+func TestAppendFormatAllocs(t *testing.T) {
+	layouts := []string{"%c", "%D", "%F", "%r", "%R", "%T", "%x", "%X", "a%nb%tc%%d", "%F %T", "%Y-%m-%dT%H:%M:%S.%f%z", "%-d %_H %^a %#Z", "%10d %10A %_4e"}
+	var buf [128]byte
+	for _, layout := range layouts {
+		allocs := testing.AllocsPerRun(100, func() {
+			strftime.AppendFormat(buf[:0], t1, layout)
+		})
+		if allocs != 0 {
+			t.Errorf("Test layout `%s`: expected no allocations; actual: %v", layout, allocs)
+		}
+	}
+}
+
+func TestFormatMatchesTime(t *testing.T) {
+	zones := []*time.Location{time.UTC, time.FixedZone("", -(4*3600 + 56*60 + 2)), time.FixedZone("", 14*3600)}
+	times := []time.Time{
+		time.Date(1, time.January, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(1600, time.February, 29, 23, 59, 59, 0, time.UTC),
+		time.Date(1900, time.March, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(1969, time.December, 31, 23, 59, 59, 0, time.UTC),
+		time.Date(2000, time.February, 29, 12, 0, 0, 0, time.UTC),
+		time.Date(2000, time.December, 31, 12, 0, 0, 0, time.UTC),
+		time.Date(2100, time.February, 28, 12, 0, 0, 0, time.UTC),
+		time.Date(9999, time.December, 31, 23, 59, 59, 0, time.UTC),
+		time.Date(-4713, time.November, 24, 12, 0, 0, 0, time.UTC),
+	}
+	// Walk a few years day by day, at an hour that changes every day.
+	for d := 0; d < 4*366; d++ {
+		times = append(times, time.Date(1999, time.January, 1, d%24, d%60, d%61, 0, time.UTC).AddDate(0, 0, d))
+	}
+
+	const layout = "%Y-%m-%d %H:%M:%S %a %z %j"
+	for _, zone := range zones {
+		for _, tm := range times {
+			tm = tm.In(zone)
+			yday := tm.YearDay()
+			expected := tm.Format("2006-01-02 15:04:05 Mon -0700 ") + string(rune('0'+yday/100)) + string(rune('0'+yday/10%10)) + string(rune('0'+yday%10))
+			if actual := strftime.Format(tm, layout); actual != expected {
+				t.Errorf("Format(%v, %q): expected: %q; actual: %q", tm, layout, expected, actual)
+			}
+		}
+	}
+}
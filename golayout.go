@@ -0,0 +1,388 @@
+// Copyright 2018 Timon Wong. All rights reserved.
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package strftime
+
+// ToGoLayout translates a strftime layout to a Go reference layout, as used
+// by time.Time.Format, that formats the same way. Composite specifiers such
+// as %c and %T are translated from their "C" locale form.
+//
+// Specifiers without a Go equivalent, such as %U, %V or %s, and most flags
+// and widths, are reported as a *LayoutError, and so is literal text that
+// Go would read as a layout element, such as "1" or "Jan". %j translates to
+// "002" from Go 1.20 on only. %f and %N translate only when they directly
+// follow a '.' or ','.
+func ToGoLayout(layout string) (string, error) {
+	g := goLayoutBuilder{layout: layout}
+	if err := g.add(layout, -1); err != nil {
+		return "", err
+	}
+	if err := g.verify(); err != nil {
+		return "", err
+	}
+	return string(g.b), nil
+}
+
+// FromGoLayout translates a Go reference layout, as used by
+// time.Time.Format, to a strftime layout that formats the same way.
+//
+// Layout elements without a strftime equivalent, such as "-07", "-070000"
+// or the trailing-zero-free fractions ".999", are reported as a
+// *LayoutError. Note that Go formats "MST" as a numeric offset for zones
+// without a name, where %Z writes nothing.
+func FromGoLayout(layout string) (string, error) {
+	var b []byte
+	for rest := layout; rest != ""; {
+		prefix, chunk, spec, suffix := nextGoChunk(rest)
+		for i := 0; i < len(prefix); i++ {
+			if prefix[i] == '%' {
+				b = append(b, '%')
+			}
+			b = append(b, prefix[i])
+		}
+		if chunk == "" {
+			break
+		}
+		if spec == "" {
+			return "", &LayoutError{
+				Layout:    layout,
+				Offset:    len(layout) - len(rest) + len(prefix),
+				Specifier: chunk,
+				Message:   "no strftime equivalent for",
+			}
+		}
+		b = append(b, spec...)
+		rest = suffix
+	}
+	return string(b), nil
+}
+
+// goLayoutBuilder assembles a Go layout, keeping track of where each
+// piece of it came from for error reporting.
+type goLayoutBuilder struct {
+	layout string // the strftime layout
+	b      []byte
+	pieces []goPiece
+}
+
+// goPiece is a span of the Go layout under construction.
+type goPiece struct {
+	start, end int    // span in the Go layout
+	src        int    // offset in the strftime layout
+	spec       string // the strftime specifier, or "" for literal text
+}
+
+// add translates layout, which is either g.layout itself or, for src >= 0,
+// the expansion of the composite specifier at offset src.
+func (g *goLayoutBuilder) add(layout string, src int) error {
+	for layout != "" {
+		prefix, std, suffix := nextStdChunk(layout)
+		at := src
+		if at < 0 {
+			at = len(g.layout) - len(layout)
+		}
+		g.piece([]byte(prefix), at, "")
+		if std == 0 {
+			break
+		}
+		if src < 0 {
+			at += len(prefix)
+		}
+		stdstr := layout[len(prefix) : len(layout)-len(suffix)]
+		layout = suffix
+
+		switch std & stdMask {
+		case stdNop:
+			continue
+		case stdUnknown:
+			return &LayoutError{Layout: g.layout, Offset: at, Specifier: stdstr}
+		case stdComposite:
+			if err := g.add(compositeLayouts[std>>stdValueShift], at); err != nil {
+				return err
+			}
+			continue
+		case stdFracSecond0:
+			if chunk := g.fracChunk(std >> stdValueShift); chunk != nil {
+				g.piece(chunk, at, stdstr)
+				continue
+			}
+		default:
+			if chunk := goChunk(std); chunk != "" {
+				g.piece([]byte(chunk), at, stdstr)
+				continue
+			}
+		}
+		return &LayoutError{Layout: g.layout, Offset: at, Specifier: stdstr, Message: "no Go layout equivalent for"}
+	}
+	return nil
+}
+
+// piece appends chunk to the Go layout.
+func (g *goLayoutBuilder) piece(chunk []byte, src int, spec string) {
+	if len(chunk) == 0 {
+		return
+	}
+	g.pieces = append(g.pieces, goPiece{start: len(g.b), end: len(g.b) + len(chunk), src: src, spec: spec})
+	g.b = append(g.b, chunk...)
+}
+
+// fracChunk returns the Go layout element for fractional seconds with the
+// given number of digits, taking the '.' or ',' before it from the literal
+// text already added, or nil if there is no such separator.
+func (g *goLayoutBuilder) fracChunk(digits int) []byte {
+	last := len(g.pieces) - 1
+	if digits < 1 || digits > 9 || last < 0 || g.pieces[last].spec != "" {
+		return nil
+	}
+	sep := g.b[len(g.b)-1]
+	if sep != '.' && sep != ',' {
+		return nil
+	}
+	g.b = g.b[:len(g.b)-1]
+	if g.pieces[last].end--; g.pieces[last].start == g.pieces[last].end {
+		g.pieces = g.pieces[:last]
+	}
+
+	chunk := []byte{sep}
+	for i := 0; i < digits; i++ {
+		chunk = append(chunk, '0')
+	}
+	return chunk
+}
+
+// verify checks that Go reads the layout as built: each translated
+// specifier as a layout element and all literal text as literal text.
+func (g *goLayoutBuilder) verify() error {
+	layout := string(g.b)
+	i := 0 // next piece to match
+	for rest := layout; ; {
+		prefix, chunk, _, suffix := nextGoChunk(rest)
+		for i < len(g.pieces) && g.pieces[i].spec == "" {
+			i++
+		}
+		start := len(layout) - len(rest) + len(prefix)
+		if chunk == "" {
+			if i < len(g.pieces) {
+				return g.ambiguous(g.pieces[i].start)
+			}
+			return nil
+		}
+		if i == len(g.pieces) || g.pieces[i].start > start {
+			return g.ambiguous(start)
+		}
+		if g.pieces[i].start < start || g.pieces[i].end != start+len(chunk) {
+			return g.ambiguous(g.pieces[i].start)
+		}
+		i++
+		rest = suffix
+	}
+}
+
+// ambiguous reports the piece of the Go layout at offset pos.
+func (g *goLayoutBuilder) ambiguous(pos int) error {
+	p := g.pieces[0]
+	for _, q := range g.pieces {
+		if q.start <= pos && pos < q.end {
+			p = q
+			break
+		}
+	}
+	spec := p.spec
+	if spec == "" {
+		spec = string(g.b[p.start:p.end])
+	}
+	return &LayoutError{Layout: g.layout, Offset: p.src, Specifier: spec, Message: "ambiguous in a Go layout:"}
+}
+
+// goChunk returns the Go layout element for std, or "" if there is none.
+func goChunk(std int) string {
+	switch std {
+	case stdLongMonth:
+		return "January"
+	case stdMonth:
+		return "Jan"
+	case stdNumMonth:
+		return "1"
+	case stdZeroMonth:
+		return "01"
+	case stdLongWeekDay:
+		return "Monday"
+	case stdWeekDay:
+		return "Mon"
+	case stdDay:
+		return "2"
+	case stdUnderDay, stdZeroDay | stdPadSpace<<stdPadShift:
+		return "_2"
+	case stdZeroDay:
+		return "02"
+	case stdHour:
+		return "15"
+	case stdHour12:
+		return "3"
+	case stdZeroHour12:
+		return "03"
+	case stdMinute:
+		return "4"
+	case stdZeroMinute:
+		return "04"
+	case stdSecond:
+		return "5"
+	case stdZeroSecond:
+		return "05"
+	case stdLongYear:
+		return "2006"
+	case stdYear:
+		return "06"
+	case stdPM:
+		return "PM"
+	case stdpm:
+		return "pm"
+	case stdTZ:
+		return "MST"
+	case stdNumTZ:
+		return "-0700"
+	case stdNumTZ | 1<<stdValueShift:
+		return "-07:00"
+	case stdNumTZ | 2<<stdValueShift:
+		return "-07:00:00"
+	case stdISO8601TZ:
+		return "Z0700"
+	case stdISO8601TZ | 1<<stdValueShift:
+		return "Z07:00"
+	case stdISO8601TZ | 2<<stdValueShift:
+		return "Z07:00:00"
+	case stdYearDay:
+		if goYearDay {
+			return "002"
+		}
+	case stdYearDay | stdPadSpace<<stdPadShift:
+		if goYearDay {
+			return "__2"
+		}
+	}
+	return ""
+}
+
+// nextGoChunk finds the first Go layout element in layout, the way the
+// time package does, and returns the text before it, the element, its
+// strftime equivalent or "" if there is none, and the text after it.
+func nextGoChunk(layout string) (prefix, chunk, spec, suffix string) {
+	for i := 0; i < len(layout); i++ {
+		n := 0 // length of the element at i
+		switch c := layout[i]; c {
+		case 'J': // January, Jan
+			if len(layout) >= i+3 && layout[i:i+3] == "Jan" {
+				if len(layout) >= i+7 && layout[i:i+7] == "January" {
+					n, spec = 7, "%B"
+				} else if !startsWithLowerCase(layout[i+3:]) {
+					n, spec = 3, "%b"
+				}
+			}
+		case 'M': // Monday, Mon, MST
+			if len(layout) >= i+3 {
+				if layout[i:i+3] == "Mon" {
+					if len(layout) >= i+6 && layout[i:i+6] == "Monday" {
+						n, spec = 6, "%A"
+					} else if !startsWithLowerCase(layout[i+3:]) {
+						n, spec = 3, "%a"
+					}
+				}
+				if layout[i:i+3] == "MST" {
+					n, spec = 3, "%Z"
+				}
+			}
+		case '0': // 01, 02, 03, 04, 05, 06, 002
+			if len(layout) >= i+2 && '1' <= layout[i+1] && layout[i+1] <= '6' {
+				n, spec = 2, [...]string{"%m", "%d", "%I", "%M", "%S", "%y"}[layout[i+1]-'1']
+			} else if goYearDay && len(layout) >= i+3 && layout[i+1] == '0' && layout[i+2] == '2' {
+				n, spec = 3, "%j"
+			}
+		case '1': // 15, 1
+			if len(layout) >= i+2 && layout[i+1] == '5' {
+				n, spec = 2, "%H"
+			} else {
+				n, spec = 1, "%-m"
+			}
+		case '2': // 2006, 2
+			if len(layout) >= i+4 && layout[i:i+4] == "2006" {
+				n, spec = 4, "%Y"
+			} else {
+				n, spec = 1, "%-d"
+			}
+		case '_': // _2, _2006, __2
+			if len(layout) >= i+2 && layout[i+1] == '2' {
+				// _2006 is really a literal _, followed by 2006.
+				if len(layout) >= i+5 && layout[i+1:i+5] == "2006" {
+					i++
+					n, spec = 4, "%Y"
+				} else {
+					n, spec = 2, "%e"
+				}
+			} else if goYearDay && len(layout) >= i+3 && layout[i+1] == '_' && layout[i+2] == '2' {
+				n, spec = 3, "%_j"
+			}
+		case '3':
+			n, spec = 1, "%-I"
+		case '4':
+			n, spec = 1, "%-M"
+		case '5':
+			n, spec = 1, "%-S"
+		case 'P': // PM
+			if len(layout) >= i+2 && layout[i+1] == 'M' {
+				n, spec = 2, "%p"
+			}
+		case 'p': // pm
+			if len(layout) >= i+2 && layout[i+1] == 'm' {
+				n, spec = 2, "%P"
+			}
+		case '-', 'Z': // -070000, -07:00:00, -0700, -07:00, -07 and the Z forms
+			flag := ""
+			if c == 'Z' {
+				flag = "#"
+			}
+			switch rest := layout[i+1:]; {
+			case len(rest) >= 6 && rest[:6] == "070000":
+				n = 7
+			case len(rest) >= 8 && rest[:8] == "07:00:00":
+				n, spec = 9, "%"+flag+"::z"
+			case len(rest) >= 4 && rest[:4] == "0700":
+				n, spec = 5, "%"+flag+"z"
+			case len(rest) >= 5 && rest[:5] == "07:00":
+				n, spec = 6, "%"+flag+":z"
+			case len(rest) >= 2 && rest[:2] == "07":
+				n = 3
+			}
+		case '.', ',': // ,000, or .000, or ,999, or .999 - repeated digits for fractional seconds.
+			if i+1 < len(layout) && (layout[i+1] == '0' || layout[i+1] == '9') {
+				ch := layout[i+1]
+				j := i + 1
+				for j < len(layout) && layout[j] == ch {
+					j++
+				}
+				// String of digits must end here - only fractional second if all digits match.
+				if j == len(layout) || !isDigit(layout[j]) {
+					n = j - i
+					if digits := n - 1; ch == '0' && digits <= 9 {
+						spec = string(c) + "%" + string(appendInt(nil, digits, 0)) + "f"
+					}
+				}
+			}
+		}
+		if n > 0 {
+			return layout[0:i], layout[i : i+n], spec, layout[i+n:]
+		}
+	}
+	return layout, "", "", ""
+}
+
+// startsWithLowerCase reports whether the string has a lower-case letter at the beginning.
+// Its purpose is to prevent matching strings like "Month" when looking for "Mon".
+func startsWithLowerCase(str string) bool {
+	if len(str) == 0 {
+		return false
+	}
+	c := str[0]
+	return 'a' <= c && c <= 'z'
+}
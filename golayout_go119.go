@@ -0,0 +1,12 @@
+// Copyright 2018 Timon Wong. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !go1.20
+// +build !go1.20
+
+package strftime
+
+// goYearDay reports whether Go layouts know the day of the year, "002"
+// and "__2", which were added in Go 1.20.
+const goYearDay = false
@@ -0,0 +1,35 @@
+// Copyright 2018 Timon Wong. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build go1.20
+// +build go1.20
+
+package strftime_test
+
+import (
+	"testing"
+
+	"github.com/imperfectgo/go-strftime"
+)
+
+func TestGoLayoutGo120(t *testing.T) {
+	for _, tt := range []struct{ layout, goLayout string }{
+		{layout: "%Y-%j", goLayout: "2006-002"},
+		{layout: "%Y %_j", goLayout: "2006 __2"},
+		// Go reads ',' as a fraction separator from Go 1.17 on.
+		{layout: "%H:%M:%S,%3f", goLayout: "15:04:05,000"},
+	} {
+		actual, err := strftime.ToGoLayout(tt.layout)
+		if err != nil || actual != tt.goLayout {
+			t.Errorf("ToGoLayout(%q): expected: %q; actual: %q, %v", tt.layout, tt.goLayout, actual, err)
+		}
+		if s, expected := strftime.Format(t1, tt.layout), t1.Format(tt.goLayout); s != expected {
+			t.Errorf("Format(%q): expected: %q; actual: %q", tt.layout, expected, s)
+		}
+		actual, err = strftime.FromGoLayout(tt.goLayout)
+		if err != nil || actual != tt.layout {
+			t.Errorf("FromGoLayout(%q): expected: %q; actual: %q, %v", tt.goLayout, tt.layout, actual, err)
+		}
+	}
+}
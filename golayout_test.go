@@ -0,0 +1,149 @@
+// Copyright 2018 Timon Wong. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package strftime_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/imperfectgo/go-strftime"
+)
+
+func TestToGoLayout(t *testing.T) {
+	cases := []struct {
+		layout   string
+		expected string
+	}{
+		{layout: "%Y-%m-%dT%H:%M:%S%#:z", expected: time.RFC3339},
+		{layout: "%Y-%m-%dT%H:%M:%S.%N%#:z", expected: "2006-01-02T15:04:05.000000000Z07:00"},
+		{layout: "%c", expected: time.ANSIC},
+		{layout: "%a, %d %b %Y %T %z", expected: time.RFC1123Z},
+		{layout: "%-I:%M%p", expected: time.Kitchen},
+		{layout: "%F %r|%D|%R", expected: "2006-01-02 03:04:05 PM|01/02/06|15:04"},
+		{layout: "%A %B %e %_d %-d %-m %-M %-S %y %P %Z %:z %::z %#z", expected: "Monday January _2 _2 2 1 4 5 06 pm MST -07:00 -07:00:00 Z0700"},
+		{layout: "%% on %d%n", expected: "% on 02\n"},
+	}
+
+	for i := range cases {
+		tt := cases[i]
+		t.Run(tt.layout, func(t *testing.T) {
+			t.Parallel()
+			actual, err := strftime.ToGoLayout(tt.layout)
+			if err != nil {
+				t.Fatalf("ToGoLayout(%q): unexpected error: %v", tt.layout, err)
+			}
+			if actual != tt.expected {
+				t.Errorf("ToGoLayout(%q): expected: %q; actual: %q", tt.layout, tt.expected, actual)
+			}
+			for _, tm := range []time.Time{t1, t2, t3, t1.In(time.FixedZone("EST", -5*3600))} {
+				if s, expected := strftime.Format(tm, tt.layout), tm.Format(actual); s != expected {
+					t.Errorf("Format(%v, %q): expected: %q; actual: %q", tm, tt.layout, expected, s)
+				}
+			}
+		})
+	}
+}
+
+func TestToGoLayoutErrors(t *testing.T) {
+	cases := []struct {
+		layout string
+		errMsg string
+	}{
+		{layout: "%Y %U", errMsg: `strftime: no Go layout equivalent for "%U" at offset 3 in layout "%Y %U"`},
+		{layout: "%G-W%V", errMsg: `strftime: no Go layout equivalent for "%G" at offset 0 in layout "%G-W%V"`},
+		{layout: "%s", errMsg: `strftime: no Go layout equivalent for "%s" at offset 0 in layout "%s"`},
+		{layout: "%-H", errMsg: `strftime: no Go layout equivalent for "%-H" at offset 0 in layout "%-H"`},
+		{layout: "%:::z", errMsg: `strftime: no Go layout equivalent for "%:::z" at offset 0 in layout "%:::z"`},
+		{layout: "%S%f", errMsg: `strftime: no Go layout equivalent for "%f" at offset 2 in layout "%S%f"`},
+		{layout: "%S.%12f", errMsg: `strftime: no Go layout equivalent for "%12f" at offset 3 in layout "%S.%12f"`},
+		{layout: "%Y %J", errMsg: `strftime: unknown specifier "%J" at offset 3 in layout "%Y %J"`},
+		{layout: "%d at 5", errMsg: `strftime: ambiguous in a Go layout: " at 5" at offset 2 in layout "%d at 5"`},
+		{layout: "100%%", errMsg: `strftime: ambiguous in a Go layout: "100%" at offset 0 in layout "100%%"`},
+		{layout: "Month %m", errMsg: ``},
+		{layout: "%buary", errMsg: `strftime: ambiguous in a Go layout: "%b" at offset 0 in layout "%buary"`},
+		{layout: "%bnoon", errMsg: `strftime: ambiguous in a Go layout: "%b" at offset 0 in layout "%bnoon"`},
+		{layout: "%Y%m", errMsg: ``},
+	}
+
+	for i := range cases {
+		tt := cases[i]
+		t.Run(tt.layout, func(t *testing.T) {
+			t.Parallel()
+			_, err := strftime.ToGoLayout(tt.layout)
+			if tt.errMsg == "" {
+				if err != nil {
+					t.Errorf("ToGoLayout(%q): unexpected error: %v", tt.layout, err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("ToGoLayout(%q): expected error", tt.layout)
+			}
+			if err.Error() != tt.errMsg {
+				t.Errorf("ToGoLayout(%q): expected error: %s; actual: %s", tt.layout, tt.errMsg, err)
+			}
+		})
+	}
+}
+
+func TestFromGoLayout(t *testing.T) {
+	cases := []struct {
+		layout   string
+		expected string
+	}{
+		{layout: time.RFC3339, expected: "%Y-%m-%dT%H:%M:%S%#:z"},
+		{layout: "2006-01-02T15:04:05.000000Z07:00", expected: "%Y-%m-%dT%H:%M:%S.%6f%#:z"},
+		{layout: time.ANSIC, expected: "%a %b %e %H:%M:%S %Y"},
+		{layout: time.RFC1123, expected: "%a, %d %b %Y %H:%M:%S %Z"},
+		{layout: time.RFC822Z, expected: "%d %b %y %H:%M %z"},
+		{layout: time.Kitchen, expected: "%-I:%M%p"},
+		{layout: "January 2, 2006 3:4:5 pm -07:00:00 Z0700", expected: "%B %-d, %Y %-I:%-M:%-S %P %::z %#z"},
+		{layout: "Month: Jan_2006 %", expected: "Month: %b_%Y %%"},
+	}
+
+	for i := range cases {
+		tt := cases[i]
+		t.Run(tt.layout, func(t *testing.T) {
+			t.Parallel()
+			actual, err := strftime.FromGoLayout(tt.layout)
+			if err != nil {
+				t.Fatalf("FromGoLayout(%q): unexpected error: %v", tt.layout, err)
+			}
+			if actual != tt.expected {
+				t.Errorf("FromGoLayout(%q): expected: %q; actual: %q", tt.layout, tt.expected, actual)
+			}
+			back, err := strftime.ToGoLayout(actual)
+			if err != nil || back != tt.layout {
+				t.Errorf("ToGoLayout(%q): expected: %q; actual: %q, %v", actual, tt.layout, back, err)
+			}
+		})
+	}
+}
+
+func TestFromGoLayoutErrors(t *testing.T) {
+	cases := []struct {
+		layout string
+		errMsg string
+	}{
+		{layout: time.RFC3339Nano, errMsg: `strftime: no strftime equivalent for ".999999999" at offset 19 in layout "2006-01-02T15:04:05.999999999Z07:00"`},
+		{layout: "15:04 -07", errMsg: `strftime: no strftime equivalent for "-07" at offset 6 in layout "15:04 -07"`},
+		{layout: "-070000", errMsg: `strftime: no strftime equivalent for "-070000" at offset 0 in layout "-070000"`},
+		{layout: "Z07", errMsg: `strftime: no strftime equivalent for "Z07" at offset 0 in layout "Z07"`},
+	}
+
+	for i := range cases {
+		tt := cases[i]
+		t.Run(tt.layout, func(t *testing.T) {
+			t.Parallel()
+			_, err := strftime.FromGoLayout(tt.layout)
+			if err == nil {
+				t.Fatalf("FromGoLayout(%q): expected error", tt.layout)
+			}
+			if err.Error() != tt.errMsg {
+				t.Errorf("FromGoLayout(%q): expected error: %s; actual: %s", tt.layout, tt.errMsg, err)
+			}
+		})
+	}
+}
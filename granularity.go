@@ -0,0 +1,354 @@
+// Copyright 2018 Timon Wong. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package strftime
+
+import (
+	"bytes"
+	"time"
+)
+
+// A Unit is a calendar unit, as reported by Granularity.
+type Unit int
+
+// Units, from the finest to the coarsest.
+const (
+	UnitNone        Unit = iota // the layout references no calendar unit
+	UnitNanosecond              // %N, %7N to %9N
+	UnitMicrosecond             // %f, %4N to %6N
+	UnitMillisecond             // %Q, %1N to %3N
+	UnitSecond                  // %S, %s, %c, %T, ...
+	UnitMinute                  // %M, %R, ...
+	UnitHour                    // %H, %I, %k, %l
+	UnitHalfDay                 // %p, %P
+	UnitDay                     // %d, %e, %j, %a, %u, %w, %D, %F, ...
+	UnitWeek                    // %U, %W, %V
+	UnitMonth                   // %m, %b, %B
+	UnitYear                    // %Y, %y, %G, %g
+	UnitCentury                 // %C
+)
+
+var unitNames = [...]string{
+	UnitNone:        "none",
+	UnitNanosecond:  "nanosecond",
+	UnitMicrosecond: "microsecond",
+	UnitMillisecond: "millisecond",
+	UnitSecond:      "second",
+	UnitMinute:      "minute",
+	UnitHour:        "hour",
+	UnitHalfDay:     "half-day",
+	UnitDay:         "day",
+	UnitWeek:        "week",
+	UnitMonth:       "month",
+	UnitYear:        "year",
+	UnitCentury:     "century",
+}
+
+// String returns the name of the unit, such as "day".
+func (u Unit) String() string {
+	if u >= 0 && int(u) < len(unitNames) {
+		return unitNames[u]
+	}
+	return "Unit(" + string(appendInt(nil, int(u), 0)) + ")"
+}
+
+// Boundaries at which the output of a layout may change, as bits of
+// granularity.bounds. Each is a set of instants on the wall clock; the
+// output changes on their union.
+const (
+	boundSecond  = 1 << iota // every second
+	boundMinute              // every minute
+	boundHour                // every hour
+	boundHalfDay             // at midnight and noon
+	boundDay                 // at midnight
+	boundWeekSun             // at midnight starting a Sunday
+	boundWeekMon             // at midnight starting a Monday
+	boundMonth               // on the first of the month
+	boundYear                // on January 1
+	boundISOYear             // on the Monday starting week 1 of an ISO 8601 year
+	boundCentury             // on January 1 of a year divisible by 100
+)
+
+// granularity records what the output of a layout depends on.
+type granularity struct {
+	frac   time.Duration // resolution of fractional seconds, 0 if none
+	bounds int           // bound* bits
+	zone   bool          // the layout references the zone
+}
+
+// Granularity returns the smallest calendar unit the layout references, or
+// UnitNone if its output does not depend on the time. Zone specifiers are
+// not units: the output of a layout that references the zone also changes
+// at zone transitions, which Floor and NextChange account for.
+//
+// Sub-second units report the finest digit shown, so "%1N", which changes
+// every 100 milliseconds, reports UnitMillisecond.
+func Granularity(layout string) Unit {
+	g := layoutGranularity(layout)
+	return g.unit()
+}
+
+// unit implements Granularity.
+func (g *granularity) unit() Unit {
+	switch {
+	case g.frac == 0:
+	case g.frac < time.Microsecond:
+		return UnitNanosecond
+	case g.frac < time.Millisecond:
+		return UnitMicrosecond
+	default:
+		return UnitMillisecond
+	}
+	units := [...]Unit{
+		UnitSecond, UnitMinute, UnitHour, UnitHalfDay, UnitDay,
+		UnitWeek, UnitWeek, UnitMonth, UnitYear, UnitYear, UnitCentury,
+	}
+	for i, u := range units {
+		if g.bounds&(1<<uint(i)) != 0 {
+			return u
+		}
+	}
+	return UnitNone
+}
+
+// Floor returns the earliest time s not after t such that Format(x, layout)
+// is the same for every x from s through t: the start of the bucket t falls
+// in. It returns the zero Time if the output of layout never changes.
+//
+// Boundaries follow the wall clock of t's location, so that a day lasts 23
+// or 25 hours across a daylight saving time transition, and weeks start on
+// Sunday for %U and on Monday for %W and %V. A transition is a boundary
+// where it changes the output; Floor(t, "%H") in the repeated hour after
+// clocks are set back returns the start of the first of the two hours.
+//
+// Custom specifiers are not supported: unknown specifiers are taken as
+// literal text, as Format does.
+func Floor(t time.Time, layout string) time.Time {
+	g := layoutGranularity(layout)
+	return g.floor(t, layout)
+}
+
+// NextChange returns the earliest time after t at which Format(x, layout)
+// differs from Format(t, layout): the start of the next bucket. It returns
+// the zero Time if the output of layout never changes.
+//
+// NextChange follows the wall clock of t's location as Floor does.
+func NextChange(t time.Time, layout string) time.Time {
+	g := layoutGranularity(layout)
+	return g.next(t, layout)
+}
+
+// constant reports whether the output of the layout never changes.
+func (g *granularity) constant() bool {
+	return g.frac == 0 && g.bounds == 0 && !g.zone
+}
+
+// floor implements Floor for the layout g was worked out from.
+func (g *granularity) floor(t time.Time, layout string) time.Time {
+	if g.constant() {
+		return time.Time{}
+	}
+	for {
+		_, offset := t.Zone()
+		s := g.wallFloor(t, offset)
+		z, ok := zoneStart(t, s)
+		if !ok {
+			return s
+		}
+		// A zone transition lies between the floor on the current wall
+		// clock and t. It is a boundary if it changes the output;
+		// otherwise the bucket continues before it.
+		if !sameOutput(z.Add(-1), t, layout) {
+			return z
+		}
+		t = z.Add(-1)
+	}
+}
+
+// next implements NextChange for the layout g was worked out from.
+func (g *granularity) next(t time.Time, layout string) time.Time {
+	if g.constant() {
+		return time.Time{}
+	}
+	u := t
+	for {
+		_, offset := u.Zone()
+		n := g.wallNext(u, offset)
+		z, ok := zoneEnd(u, n)
+		if !ok {
+			return n
+		}
+		if !sameOutput(z, t, layout) {
+			return z
+		}
+		u = z
+	}
+}
+
+// sameOutput reports whether layout formats a and b the same.
+func sameOutput(a, b time.Time, layout string) bool {
+	var bufA, bufB [64]byte
+	return bytes.Equal(AppendFormat(bufA[:0], a, layout), AppendFormat(bufB[:0], b, layout))
+}
+
+// layoutGranularity works out what the output of layout depends on.
+func layoutGranularity(layout string) granularity {
+	var g granularity
+	g.add(layout)
+	return g
+}
+
+func (g *granularity) add(layout string) {
+	for layout != "" {
+		_, std, suffix := nextStdChunk(layout)
+		layout = suffix
+		switch std & stdMask {
+		case stdLongMonth, stdMonth, stdNumMonth, stdZeroMonth:
+			g.bounds |= boundMonth
+		case stdLongWeekDay, stdWeekDay, stdZeroBasedNumWeekDay, stdNumWeekDay,
+			stdDay, stdUnderDay, stdZeroDay, stdYearDay:
+			g.bounds |= boundDay
+		case stdWeekOfYear:
+			g.bounds |= boundWeekSun | boundYear
+		case stdMonFirstWeekOfYear:
+			g.bounds |= boundWeekMon | boundYear
+		case stdHour, stdHour12, stdZeroHour12, stdUnderHour, stdUnderHour12:
+			g.bounds |= boundHour
+		case stdMinute, stdZeroMinute:
+			g.bounds |= boundMinute
+		case stdSecond, stdZeroSecond, stdUnix:
+			g.bounds |= boundSecond
+		case stdLongYear, stdYear:
+			g.bounds |= boundYear
+		case stdFirstTwoDigitYear:
+			g.bounds |= boundCentury
+		case stdISO8601WeekYear, stdISO8601LongWeekYear:
+			g.bounds |= boundISOYear
+		case stdISO8601Week:
+			g.bounds |= boundWeekMon
+		case stdPM, stdpm:
+			g.bounds |= boundHalfDay
+		case stdTZ, stdNumTZ, stdISO8601TZ:
+			g.zone = true
+		case stdUnixMilli:
+			g.addFrac(time.Millisecond)
+		case stdFracSecond0, stdFracSecond9:
+			res := time.Second
+			for digits := std >> stdValueShift; digits > 0 && res > time.Nanosecond; digits-- {
+				res /= 10
+			}
+			g.addFrac(res)
+		case stdComposite:
+			g.add(compositeLayout(nil, std>>stdValueShift, true))
+		}
+	}
+}
+
+func (g *granularity) addFrac(res time.Duration) {
+	if g.frac == 0 || res < g.frac {
+		g.frac = res
+	}
+}
+
+// wallFloor returns the latest boundary not after t, assuming the zone
+// offset stays as given. It returns the zero Time if there is none.
+func (g *granularity) wallFloor(t time.Time, offset int) time.Time {
+	w := t.UTC().Add(time.Duration(offset) * time.Second)
+	var floor time.Time
+	g.each(w, func(f, _ time.Time) {
+		if f.After(floor) {
+			floor = f
+		}
+	})
+	if floor.IsZero() {
+		return floor
+	}
+	return floor.Add(-time.Duration(offset) * time.Second).In(t.Location())
+}
+
+// wallNext returns the earliest boundary after t, assuming the zone offset
+// stays as given. It returns the zero Time if there is none.
+func (g *granularity) wallNext(t time.Time, offset int) time.Time {
+	w := t.UTC().Add(time.Duration(offset) * time.Second)
+	var next time.Time
+	g.each(w, func(_, n time.Time) {
+		if next.IsZero() || n.Before(next) {
+			next = n
+		}
+	})
+	if next.IsZero() {
+		return next
+	}
+	return next.Add(-time.Duration(offset) * time.Second).In(t.Location())
+}
+
+// each calls fn with the boundary not after w and the boundary after it for
+// each kind of boundary of g. w and the boundaries are wall clock times,
+// held in UTC.
+func (g *granularity) each(w time.Time, fn func(floor, next time.Time)) {
+	if g.frac != 0 {
+		f := w.Truncate(g.frac)
+		fn(f, f.Add(g.frac))
+	}
+	for _, d := range [...]struct {
+		bound int
+		d     time.Duration
+	}{{boundSecond, time.Second}, {boundMinute, time.Minute}, {boundHour, time.Hour}} {
+		if g.bounds&d.bound != 0 {
+			f := w.Truncate(d.d)
+			fn(f, f.Add(d.d))
+		}
+	}
+	if g.bounds&^(boundSecond|boundMinute|boundHour) == 0 {
+		return
+	}
+
+	year, month, day := w.Date()
+	midnight := time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	if g.bounds&boundHalfDay != 0 {
+		f := midnight
+		if w.Hour() >= 12 {
+			f = f.Add(12 * time.Hour)
+		}
+		fn(f, f.Add(12*time.Hour))
+	}
+	if g.bounds&boundDay != 0 {
+		fn(midnight, midnight.AddDate(0, 0, 1))
+	}
+	if g.bounds&boundWeekSun != 0 {
+		f := midnight.AddDate(0, 0, -int(w.Weekday()))
+		fn(f, f.AddDate(0, 0, 7))
+	}
+	if g.bounds&boundWeekMon != 0 {
+		f := midnight.AddDate(0, 0, -(int(w.Weekday())+6)%7)
+		fn(f, f.AddDate(0, 0, 7))
+	}
+	if g.bounds&boundMonth != 0 {
+		f := time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)
+		fn(f, f.AddDate(0, 1, 0))
+	}
+	if g.bounds&boundYear != 0 {
+		f := time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
+		fn(f, f.AddDate(1, 0, 0))
+	}
+	if g.bounds&boundISOYear != 0 {
+		isoYear, _ := w.ISOWeek()
+		fn(isoYearStart(isoYear), isoYearStart(isoYear+1))
+	}
+	if g.bounds&boundCentury != 0 {
+		c := year / 100
+		if year%100 < 0 {
+			c--
+		}
+		f := time.Date(c*100, time.January, 1, 0, 0, 0, 0, time.UTC)
+		fn(f, f.AddDate(100, 0, 0))
+	}
+}
+
+// isoYearStart returns the Monday starting week 1 of the ISO 8601 year,
+// the week holding January 4.
+func isoYearStart(year int) time.Time {
+	jan4 := time.Date(year, time.January, 4, 0, 0, 0, 0, time.UTC)
+	return jan4.AddDate(0, 0, -(int(jan4.Weekday())+6)%7)
+}
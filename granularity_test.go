@@ -0,0 +1,332 @@
+// Copyright 2018 Timon Wong. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package strftime_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/imperfectgo/go-strftime"
+)
+
+func TestGranularity(t *testing.T) {
+	tests := []struct {
+		layout   string
+		expected strftime.Unit
+	}{
+		{layout: "app.log", expected: strftime.UnitNone},
+		{layout: "%Z %z %%", expected: strftime.UnitNone},
+		{layout: "%Y%m%d-%H", expected: strftime.UnitHour},
+		{layout: "%F", expected: strftime.UnitDay},
+		{layout: "%c", expected: strftime.UnitSecond},
+		{layout: "%R", expected: strftime.UnitMinute},
+		{layout: "%Y-%U", expected: strftime.UnitWeek},
+		{layout: "%G-W%V", expected: strftime.UnitWeek},
+		{layout: "%B %Y", expected: strftime.UnitMonth},
+		{layout: "%y", expected: strftime.UnitYear},
+		{layout: "%C", expected: strftime.UnitCentury},
+		{layout: "%p", expected: strftime.UnitHalfDay},
+		{layout: "%l%P", expected: strftime.UnitHour},
+		{layout: "%s", expected: strftime.UnitSecond},
+		{layout: "%Q", expected: strftime.UnitMillisecond},
+		{layout: "%1N", expected: strftime.UnitMillisecond},
+		{layout: "%f", expected: strftime.UnitMicrosecond},
+		{layout: "%N", expected: strftime.UnitNanosecond},
+	}
+
+	for _, tt := range tests {
+		if actual := strftime.Granularity(tt.layout); actual != tt.expected {
+			t.Errorf("Granularity(%q): expected: %v; actual: %v", tt.layout, tt.expected, actual)
+		}
+	}
+}
+
+func TestFloorNextChange(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip("time zone database unavailable:", err)
+	}
+	date := func(loc *time.Location, year int, month time.Month, day, hour, min, sec, nsec int) time.Time {
+		return time.Date(year, month, day, hour, min, sec, nsec, loc)
+	}
+
+	tests := []struct {
+		layout string
+		t      time.Time
+		floor  time.Time
+		next   time.Time
+	}{
+		{
+			layout: "%Y%m%d-%H",
+			t:      date(time.UTC, 2008, 9, 3, 20, 4, 26, 0),
+			floor:  date(time.UTC, 2008, 9, 3, 20, 0, 0, 0),
+			next:   date(time.UTC, 2008, 9, 3, 21, 0, 0, 0),
+		},
+		{
+			layout: "%I:%M %p",
+			t:      date(time.UTC, 2008, 9, 3, 20, 4, 26, 0),
+			floor:  date(time.UTC, 2008, 9, 3, 20, 4, 0, 0),
+			next:   date(time.UTC, 2008, 9, 3, 20, 5, 0, 0),
+		},
+		{
+			layout: "%p",
+			t:      date(time.UTC, 2008, 9, 3, 20, 4, 26, 0),
+			floor:  date(time.UTC, 2008, 9, 3, 12, 0, 0, 0),
+			next:   date(time.UTC, 2008, 9, 4, 0, 0, 0, 0),
+		},
+		{
+			layout: "%3N",
+			t:      date(time.UTC, 2008, 9, 3, 20, 4, 26, 654321000),
+			floor:  date(time.UTC, 2008, 9, 3, 20, 4, 26, 654000000),
+			next:   date(time.UTC, 2008, 9, 3, 20, 4, 26, 655000000),
+		},
+		{
+			// Week 00 runs from January 1 to the first Sunday.
+			layout: "%U",
+			t:      date(time.UTC, 2021, 1, 1, 12, 0, 0, 0),
+			floor:  date(time.UTC, 2021, 1, 1, 0, 0, 0, 0),
+			next:   date(time.UTC, 2021, 1, 3, 0, 0, 0, 0),
+		},
+		{
+			layout: "%W",
+			t:      date(time.UTC, 2021, 1, 1, 12, 0, 0, 0),
+			floor:  date(time.UTC, 2021, 1, 1, 0, 0, 0, 0),
+			next:   date(time.UTC, 2021, 1, 4, 0, 0, 0, 0),
+		},
+		{
+			// ISO week 53 of 2020 crosses the new year.
+			layout: "%V",
+			t:      date(time.UTC, 2021, 1, 1, 12, 0, 0, 0),
+			floor:  date(time.UTC, 2020, 12, 28, 0, 0, 0, 0),
+			next:   date(time.UTC, 2021, 1, 4, 0, 0, 0, 0),
+		},
+		{
+			layout: "%G",
+			t:      date(time.UTC, 2021, 1, 1, 12, 0, 0, 0),
+			floor:  date(time.UTC, 2019, 12, 30, 0, 0, 0, 0),
+			next:   date(time.UTC, 2021, 1, 4, 0, 0, 0, 0),
+		},
+		{
+			layout: "%C",
+			t:      date(time.UTC, 2021, 1, 1, 12, 0, 0, 0),
+			floor:  date(time.UTC, 2000, 1, 1, 0, 0, 0, 0),
+			next:   date(time.UTC, 2100, 1, 1, 0, 0, 0, 0),
+		},
+		{
+			layout: "app.log",
+			t:      date(time.UTC, 2021, 1, 1, 12, 0, 0, 0),
+		},
+		{
+			// The day clocks are set forward lasts 23 hours.
+			layout: "%F",
+			t:      date(newYork, 2024, 3, 10, 12, 0, 0, 0),
+			floor:  date(time.UTC, 2024, 3, 10, 5, 0, 0, 0),
+			next:   date(time.UTC, 2024, 3, 11, 4, 0, 0, 0),
+		},
+		{
+			// 02:00 EST is skipped.
+			layout: "%H",
+			t:      date(newYork, 2024, 3, 10, 1, 30, 0, 0),
+			floor:  date(time.UTC, 2024, 3, 10, 6, 0, 0, 0),
+			next:   date(time.UTC, 2024, 3, 10, 7, 0, 0, 0),
+		},
+		{
+			// 01:00 to 02:00 repeats, first in EDT, then in EST.
+			layout: "%H",
+			t:      date(time.UTC, 2024, 11, 3, 6, 30, 0, 0).In(newYork),
+			floor:  date(time.UTC, 2024, 11, 3, 5, 0, 0, 0),
+			next:   date(time.UTC, 2024, 11, 3, 7, 0, 0, 0),
+		},
+		{
+			layout: "%H %Z",
+			t:      date(time.UTC, 2024, 11, 3, 6, 30, 0, 0).In(newYork),
+			floor:  date(time.UTC, 2024, 11, 3, 6, 0, 0, 0),
+			next:   date(time.UTC, 2024, 11, 3, 7, 0, 0, 0),
+		},
+		{
+			layout: "%Z",
+			t:      date(newYork, 2024, 7, 1, 0, 0, 0, 0),
+			floor:  date(time.UTC, 2024, 3, 10, 7, 0, 0, 0),
+			next:   date(time.UTC, 2024, 11, 3, 6, 0, 0, 0),
+		},
+	}
+
+	for _, tt := range tests {
+		if actual := strftime.Floor(tt.t, tt.layout); !actual.Equal(tt.floor) {
+			t.Errorf("Floor(%v, %q): expected: %v; actual: %v", tt.t, tt.layout, tt.floor, actual)
+		}
+		if actual := strftime.NextChange(tt.t, tt.layout); !actual.Equal(tt.next) {
+			t.Errorf("NextChange(%v, %q): expected: %v; actual: %v", tt.t, tt.layout, tt.next, actual)
+		}
+	}
+}
+
+// TestFloorNextChangeFarFuture checks times past the end of the transition
+// table, where daylight saving time follows a rule instead.
+func TestFloorNextChangeFarFuture(t *testing.T) {
+	london, err := time.LoadLocation("Europe/London")
+	if err != nil {
+		t.Skip("time zone database unavailable:", err)
+	}
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip("time zone database unavailable:", err)
+	}
+
+	tests := []struct {
+		layout string
+		t      time.Time
+		floor  time.Time
+		next   time.Time
+	}{
+		{
+			layout: "%Y",
+			t:      time.Date(2040, 6, 1, 0, 0, 0, 0, london),
+			floor:  time.Date(2040, 1, 1, 0, 0, 0, 0, time.UTC),
+			next:   time.Date(2041, 1, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			layout: "%Y",
+			t:      time.Date(2040, 6, 1, 0, 0, 0, 0, newYork),
+			floor:  time.Date(2040, 1, 1, 5, 0, 0, 0, time.UTC),
+			next:   time.Date(2041, 1, 1, 5, 0, 0, 0, time.UTC),
+		},
+		{
+			layout: "%C",
+			t:      time.Date(2022, 6, 1, 0, 0, 0, 0, london),
+			floor:  time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC),
+			next:   time.Date(2100, 1, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			layout: "%Z",
+			t:      time.Date(2040, 12, 31, 12, 0, 0, 0, london),
+			floor:  time.Date(2040, 10, 28, 1, 0, 0, 0, time.UTC),
+			next:   time.Date(2041, 3, 31, 1, 0, 0, 0, time.UTC),
+		},
+		{
+			layout: "%H %Z",
+			t:      time.Date(2040, 12, 31, 23, 30, 0, 0, london),
+			floor:  time.Date(2040, 12, 31, 23, 0, 0, 0, time.UTC),
+			next:   time.Date(2041, 1, 1, 0, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tt := range tests {
+		var floor, next time.Time
+		if !within(5*time.Second, func() {
+			floor = strftime.Floor(tt.t, tt.layout)
+			next = strftime.NextChange(tt.t, tt.layout)
+		}) {
+			t.Errorf("Floor or NextChange(%v, %q) did not return", tt.t, tt.layout)
+			continue
+		}
+		if !floor.Equal(tt.floor) {
+			t.Errorf("Floor(%v, %q): expected: %v; actual: %v", tt.t, tt.layout, tt.floor, floor)
+		}
+		if !next.Equal(tt.next) {
+			t.Errorf("NextChange(%v, %q): expected: %v; actual: %v", tt.t, tt.layout, tt.next, next)
+		}
+	}
+}
+
+// within reports whether fn returns within d. Otherwise fn is left running.
+func within(d time.Duration, fn func()) bool {
+	done := make(chan struct{})
+	go func() {
+		fn()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return true
+	case <-time.After(d):
+		return false
+	}
+}
+
+// TestFloorNextChangeZones checks that Floor and NextChange bound the
+// bucket of t exactly, around daylight saving time transitions of zones
+// with unusual offsets and transition times.
+func TestFloorNextChangeZones(t *testing.T) {
+	layouts := []string{
+		"%Y%m%d-%H", "%F", "%H", "%H %Z", "%H:%M", "%I %p", "%p", "%T%z",
+		"%U", "%W", "%Y-W%V", "%G", "%j", "%a", "%c", "%s", "%Z",
+	}
+	zones := []struct {
+		name  string
+		times []time.Time // UTC times to test around
+	}{
+		{"America/New_York", []time.Time{
+			time.Date(2024, 3, 10, 7, 0, 0, 0, time.UTC),
+			time.Date(2024, 11, 3, 6, 0, 0, 0, time.UTC),
+			time.Date(2024, 1, 1, 5, 0, 0, 0, time.UTC),
+		}},
+		{"Europe/London", []time.Time{ // past the transition table
+			time.Date(2040, 10, 28, 1, 0, 0, 0, time.UTC),
+			time.Date(2040, 12, 31, 12, 0, 0, 0, time.UTC),
+		}},
+		{"America/Sao_Paulo", []time.Time{ // transitions at midnight
+			time.Date(2018, 11, 4, 3, 0, 0, 0, time.UTC),
+			time.Date(2019, 2, 17, 2, 0, 0, 0, time.UTC),
+		}},
+		{"Australia/Lord_Howe", []time.Time{ // half-hour shifts
+			time.Date(2024, 4, 6, 15, 0, 0, 0, time.UTC),
+			time.Date(2024, 10, 5, 15, 30, 0, 0, time.UTC),
+		}},
+		{"Asia/Kolkata", []time.Time{ // +05:30
+			time.Date(2024, 6, 30, 18, 30, 0, 0, time.UTC),
+		}},
+	}
+
+	for _, zone := range zones {
+		loc, err := time.LoadLocation(zone.name)
+		if err != nil {
+			t.Skip("time zone database unavailable:", err)
+		}
+		for _, around := range zone.times {
+			for d := -26 * time.Hour; d <= 26*time.Hour; d += 17 * time.Minute {
+				tm := around.Add(d).In(loc)
+				for _, layout := range layouts {
+					checkBucket(t, tm, layout)
+				}
+			}
+		}
+	}
+}
+
+func checkBucket(t *testing.T, tm time.Time, layout string) {
+	t.Helper()
+	s := strftime.Format(tm, layout)
+	floor := strftime.Floor(tm, layout)
+	next := strftime.NextChange(tm, layout)
+	if floor.IsZero() || next.IsZero() {
+		if layout != "%Z" {
+			t.Errorf("%q at %v: zero Floor %v or NextChange %v", layout, tm, floor, next)
+		}
+		return
+	}
+	if floor.After(tm) || !next.After(tm) {
+		t.Errorf("%q at %v: expected %v <= t < %v", layout, tm, floor, next)
+		return
+	}
+	if strftime.Format(floor.Add(-1), layout) == s {
+		t.Errorf("%q at %v: output unchanged before Floor %v", layout, tm, floor)
+	}
+	if strftime.Format(next, layout) == s {
+		t.Errorf("%q at %v: output unchanged at NextChange %v", layout, tm, next)
+	}
+	const samples = 16
+	step := next.Sub(floor) / samples
+	for i := 0; i <= samples; i++ {
+		x := floor.Add(time.Duration(i) * step)
+		if i == samples {
+			x = next.Add(-1)
+		}
+		if actual := strftime.Format(x, layout); actual != s {
+			t.Errorf("%q at %v: output %q at %v within [%v, %v) differs from %q", layout, tm, actual, x, floor, next, s)
+			return
+		}
+	}
+}
@@ -0,0 +1,142 @@
+// Copyright 2018 Timon Wong. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package strftime
+
+import (
+	"time"
+)
+
+// Layout is a precompiled strftime layout.
+//
+// Compiling a layout tokenizes it once, so formatting with a Layout
+// does not need to rescan the layout string on every call. A Layout is
+// safe for concurrent use by multiple goroutines.
+type Layout struct {
+	layout string
+	loc    *Locale
+	ops    []op
+	need   int // stdNeed* bits of all ops
+}
+
+// op is a single step of a compiled layout: literal text followed by
+// an optional std value or, for custom and unknown specifiers, a func.
+type op struct {
+	lit string
+	std int
+	fn  SpecifierFunc
+}
+
+// Compile parses a strftime layout and returns a Layout that can be used
+// to format times without rescanning the layout.
+//
+// Compile accepts exactly the layouts Format does; unknown specifiers are
+// kept as literal text. The error is reserved for layouts that cannot be
+// compiled and is currently always nil.
+func Compile(layout string) (*Layout, error) {
+	return CompileLocale(layout, nil)
+}
+
+// CompileLocale is like Compile but the returned Layout formats as
+// FormatLocale does with loc. A nil loc selects English.
+func CompileLocale(layout string, loc *Locale) (*Layout, error) {
+	return newLayout(layout, loc, nil), nil
+}
+
+// newLayout compiles layout for loc, resolving custom and unknown
+// specifiers with opts if not nil.
+func newLayout(layout string, loc *Locale, opts *Options) *Layout {
+	l := &Layout{layout: layout, loc: loc}
+	if lit := l.compile(opts, "", layout, false); lit != "" {
+		l.ops = append(l.ops, op{lit: lit, std: stdNop})
+	}
+	return l
+}
+
+// compile appends the ops of layout to l, with lit as pending literal
+// text, and returns the literal text left over at the end of layout.
+// nested reports whether layout is the expansion of a composite; opts,
+// if not nil, resolves custom and unknown specifiers.
+func (l *Layout) compile(opts *Options, lit, layout string, nested bool) string {
+	for layout != "" {
+		prefix, std, suffix := nextStdChunk(layout)
+		lit += prefix
+		if std == 0 {
+			break
+		}
+		stdstr := layout[len(prefix) : len(layout)-len(suffix)]
+		if std == stdUnknown && opts != nil {
+			if fn, n := opts.Specifiers.lookup(layout[len(prefix)+1:]); fn != nil {
+				l.ops = append(l.ops, op{lit: lit, std: stdNop, fn: fn})
+				lit = ""
+				layout = layout[len(prefix)+1+n:]
+				continue
+			}
+		}
+		layout = suffix
+
+		switch std & stdMask {
+		case stdNop:
+			continue
+		case stdUnknown:
+			s, fn := opts.unknownOp(stdstr)
+			lit += s
+			if fn != nil {
+				l.ops = append(l.ops, op{lit: lit, std: stdNop, fn: fn})
+				lit = ""
+			}
+			continue
+		case stdComposite:
+			lit = l.compile(opts, lit, compositeLayout(l.loc, std>>stdValueShift, nested), true)
+			continue
+		}
+		l.ops = append(l.ops, op{lit: lit, std: std})
+		l.need |= std & (stdNeedDate | stdNeedClock | stdNeedISOISO8601Week)
+		lit = ""
+	}
+	return lit
+}
+
+// MustCompile is like Compile but panics if the layout cannot be compiled.
+func MustCompile(layout string) *Layout {
+	l, err := Compile(layout)
+	if err != nil {
+		panic(`strftime: Compile(` + quote(layout) + `): ` + err.Error())
+	}
+	return l
+}
+
+// String returns the source text used to compile the layout.
+func (l *Layout) String() string {
+	return l.layout
+}
+
+// Format returns a textual representation of the time value formatted
+// according to the compiled layout.
+func (l *Layout) Format(t time.Time) string {
+	const bufSize = 64
+	var b [bufSize]byte
+	buf := l.AppendFormat(b[:0], t)
+	return string(buf)
+}
+
+// AppendFormat is like Format but appends the textual
+// representation to b and returns the extended buffer.
+func (l *Layout) AppendFormat(b []byte, t time.Time) []byte {
+	var f fields
+	f.init(t, l.loc)
+
+	// The compiled layout knows up front which fields it needs.
+	f.compute(l.need)
+	for i := range l.ops {
+		op := &l.ops[i]
+		b = append(b, op.lit...)
+		if op.fn != nil {
+			b = appendFunc(b, op.fn, t)
+			continue
+		}
+		b = f.appendStd(b, op.std)
+	}
+	return b
+}
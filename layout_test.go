@@ -0,0 +1,42 @@
+// Copyright 2018 Timon Wong. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package strftime_test
+
+import (
+	"testing"
+
+	"github.com/imperfectgo/go-strftime"
+)
+
+func TestLayoutFormat(t *testing.T) {
+	for i := range tc {
+		tt := tc[i]
+		t.Run(tt.layout, func(t *testing.T) {
+			t.Parallel()
+			l, err := strftime.Compile(tt.layout)
+			if err != nil {
+				t.Fatalf("Compile(%q): unexpected error: %v", tt.layout, err)
+			}
+			if l.String() != tt.layout {
+				t.Errorf("Compile(%q).String(): actual: %q", tt.layout, l.String())
+			}
+			actual := l.Format(tt.time)
+			if actual != tt.expected {
+				t.Errorf("Test layout `%s`: expected: %q; actual: %q", tt.layout, tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestLayoutAppendFormatAllocs(t *testing.T) {
+	l := strftime.MustCompile("%Y-%m-%dT%H:%M:%S.%f%z %a %B %j %V %p")
+	var buf [128]byte
+	allocs := testing.AllocsPerRun(100, func() {
+		l.AppendFormat(buf[:0], t1)
+	})
+	if allocs != 0 {
+		t.Errorf("expected no allocations; actual: %v", allocs)
+	}
+}
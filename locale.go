@@ -0,0 +1,146 @@
+// Copyright 2018 Timon Wong. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package strftime
+
+import (
+	"time"
+)
+
+// Locale holds the names used by the textual specifiers and the
+// preferred representations used by the locale-dependent composites.
+//
+// A nil *Locale stands for the built-in English names and the "C" locale
+// representations, which are formatted without any extra cost.
+type Locale struct {
+	LongMonthNames  [12]string // %B, January first
+	ShortMonthNames [12]string // %b and %h, January first
+	LongDayNames    [7]string  // %A, Sunday first
+	ShortDayNames   [7]string  // %a, Sunday first
+	AM              string     // %p before noon; %P uses its lower-case form
+	PM              string     // %p from noon on; %P uses its lower-case form
+
+	// Preferred representations, as strftime layouts. An empty layout
+	// falls back to the "C" locale one. Composite specifiers used inside
+	// these layouts always expand to their "C" locale form.
+	DateTimeLayout string // %c, "%a %b %e %H:%M:%S %Y" in the "C" locale
+	DateLayout     string // %x, "%m/%d/%Y" in the "C" locale
+	TimeLayout     string // %X, "%H:%M:%S" in the "C" locale
+	Time12Layout   string // %r, "%I:%M:%S %p" in the "C" locale
+}
+
+// Predefined locales, with names as found in the glibc locale data.
+var (
+	// LocaleEnUS is English (United States).
+	LocaleEnUS = &Locale{
+		LongMonthNames:  [12]string{"January", "February", "March", "April", "May", "June", "July", "August", "September", "October", "November", "December"},
+		ShortMonthNames: [12]string{"Jan", "Feb", "Mar", "Apr", "May", "Jun", "Jul", "Aug", "Sep", "Oct", "Nov", "Dec"},
+		LongDayNames:    [7]string{"Sunday", "Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday"},
+		ShortDayNames:   [7]string{"Sun", "Mon", "Tue", "Wed", "Thu", "Fri", "Sat"},
+		AM:              "AM",
+		PM:              "PM",
+		DateTimeLayout:  "%a %d %b %Y %r %Z",
+		DateLayout:      "%m/%d/%Y",
+		TimeLayout:      "%r",
+		Time12Layout:    "%I:%M:%S %p",
+	}
+
+	// LocaleDeDE is German (Germany).
+	LocaleDeDE = &Locale{
+		LongMonthNames:  [12]string{"Januar", "Februar", "März", "April", "Mai", "Juni", "Juli", "August", "September", "Oktober", "November", "Dezember"},
+		ShortMonthNames: [12]string{"Jan", "Feb", "Mär", "Apr", "Mai", "Jun", "Jul", "Aug", "Sep", "Okt", "Nov", "Dez"},
+		LongDayNames:    [7]string{"Sonntag", "Montag", "Dienstag", "Mittwoch", "Donnerstag", "Freitag", "Samstag"},
+		ShortDayNames:   [7]string{"So", "Mo", "Di", "Mi", "Do", "Fr", "Sa"},
+		DateTimeLayout:  "%a %d %b %Y %T %Z",
+		DateLayout:      "%d.%m.%Y",
+		TimeLayout:      "%T",
+	}
+
+	// LocaleJaJP is Japanese (Japan).
+	LocaleJaJP = &Locale{
+		LongMonthNames:  [12]string{"1月", "2月", "3月", "4月", "5月", "6月", "7月", "8月", "9月", "10月", "11月", "12月"},
+		ShortMonthNames: [12]string{"1月", "2月", "3月", "4月", "5月", "6月", "7月", "8月", "9月", "10月", "11月", "12月"},
+		LongDayNames:    [7]string{"日曜日", "月曜日", "火曜日", "水曜日", "木曜日", "金曜日", "土曜日"},
+		ShortDayNames:   [7]string{"日", "月", "火", "水", "木", "金", "土"},
+		AM:              "午前",
+		PM:              "午後",
+		DateTimeLayout:  "%Y年%m月%d日 %H時%M分%S秒",
+		DateLayout:      "%Y/%m/%d",
+		TimeLayout:      "%H時%M分%S秒",
+		Time12Layout:    "%p%I時%M分%S秒",
+	}
+
+	// LocalePtBR is Portuguese (Brazil).
+	LocalePtBR = &Locale{
+		LongMonthNames:  [12]string{"janeiro", "fevereiro", "março", "abril", "maio", "junho", "julho", "agosto", "setembro", "outubro", "novembro", "dezembro"},
+		ShortMonthNames: [12]string{"jan", "fev", "mar", "abr", "mai", "jun", "jul", "ago", "set", "out", "nov", "dez"},
+		LongDayNames:    [7]string{"domingo", "segunda", "terça", "quarta", "quinta", "sexta", "sábado"},
+		ShortDayNames:   [7]string{"dom", "seg", "ter", "qua", "qui", "sex", "sáb"},
+		DateTimeLayout:  "%a %d %b %Y %T",
+		DateLayout:      "%d/%m/%Y",
+		TimeLayout:      "%T",
+	}
+)
+
+// FormatLocale is like Format but the textual specifiers use the names
+// of loc, and %c, %x, %X and %r expand to its preferred representations.
+// A nil loc selects English and the "C" locale representations.
+func FormatLocale(t time.Time, layout string, loc *Locale) string {
+	const bufSize = 64
+	var b [bufSize]byte
+	buf := AppendFormatLocale(b[:0], t, layout, loc)
+	return string(buf)
+}
+
+// AppendFormatLocale is like FormatLocale but appends the textual
+// representation to b and returns the extended buffer.
+func AppendFormatLocale(b []byte, t time.Time, layout string, loc *Locale) []byte {
+	var f fields
+	f.init(t, loc)
+
+	return f.appendLayout(b, layout)
+}
+
+// compositeLayout returns the expansion of the composite specifier idx in
+// loc. Composites nested in the expansion of another one always expand to
+// their "C" locale form, which keeps the expansion finite.
+func compositeLayout(loc *Locale, idx int, nested bool) string {
+	if loc != nil && !nested {
+		var layout string
+		switch idx {
+		case compositeDateTime:
+			layout = loc.DateTimeLayout
+		case compositeDate:
+			layout = loc.DateLayout
+		case compositeTime:
+			layout = loc.TimeLayout
+		case compositeTime12:
+			layout = loc.Time12Layout
+		}
+		if layout != "" {
+			return layout
+		}
+	}
+	return compositeLayouts[idx]
+}
+
+// dayPeriod returns the AM or PM designator for the given hour.
+func (l *Locale) dayPeriod(hour int) string {
+	if hour >= 12 {
+		return l.PM
+	}
+	return l.AM
+}
+
+// appendLower appends s to b with ASCII letters converted to lower case.
+func appendLower(b []byte, s string) []byte {
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if 'A' <= c && c <= 'Z' {
+			c += 'a' - 'A'
+		}
+		b = append(b, c)
+	}
+	return b
+}
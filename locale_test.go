@@ -0,0 +1,79 @@
+// Copyright 2018 Timon Wong. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package strftime_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/imperfectgo/go-strftime"
+)
+
+func TestFormatLocale(t *testing.T) {
+	cases := []struct {
+		locale   *strftime.Locale
+		layout   string
+		expected string
+	}{
+		{locale: nil, layout: "%a %A %b %B %p %P", expected: "Mon Monday Jul July PM pm"},
+		{locale: strftime.LocaleEnUS, layout: "%a %A %b %B %p %P", expected: "Mon Monday Jul July PM pm"},
+		{locale: strftime.LocaleDeDE, layout: "%a, %d. %B %Y", expected: "Mo, 09. Juli 2018"},
+		{locale: strftime.LocaleDeDE, layout: "%A %b%p", expected: "Montag Jul"},
+		{locale: strftime.LocaleJaJP, layout: "%Y年%B%d日(%a) %p%I時", expected: "2018年7月09日(月) 午後01時"},
+		{locale: strftime.LocaleJaJP, layout: "%A %b %P", expected: "月曜日 7月 午後"},
+		{locale: strftime.LocalePtBR, layout: "%A, %d de %B de %Y", expected: "segunda, 09 de julho de 2018"},
+		{locale: strftime.LocalePtBR, layout: "%a %b", expected: "seg jul"},
+		{locale: nil, layout: "%c|%x|%X|%r", expected: "Mon Jul  9 13:14:15 2018|07/09/2018|13:14:15|01:14:15 PM"},
+		{locale: strftime.LocaleEnUS, layout: "%c|%x|%X|%r", expected: "Mon 09 Jul 2018 01:14:15 PM UTC|07/09/2018|01:14:15 PM|01:14:15 PM"},
+		{locale: strftime.LocaleDeDE, layout: "%c|%x|%X|%r", expected: "Mo 09 Jul 2018 13:14:15 UTC|09.07.2018|13:14:15|01:14:15 "},
+		{locale: strftime.LocaleJaJP, layout: "%c|%x|%X|%r", expected: "2018年07月09日 13時14分15秒|2018/07/09|13時14分15秒|午後01時14分15秒"},
+		{locale: strftime.LocalePtBR, layout: "%c|%x|%X|%r", expected: "seg 09 jul 2018 13:14:15|09/07/2018|13:14:15|01:14:15 "},
+		{locale: &strftime.Locale{DateLayout: "%x %D"}, layout: "%x", expected: "07/09/2018 07/09/18"},
+		{locale: strftime.LocaleDeDE, layout: "%^A %^B", expected: "MONTAG JULI"},
+		{locale: strftime.LocaleDeDE, layout: "%^b", expected: "JUL"},
+		{locale: strftime.LocalePtBR, layout: "%#a %^b", expected: "SEG JUL"},
+		{locale: strftime.LocaleJaJP, layout: "%^A %#p", expected: "月曜日 午後"},
+	}
+
+	for i := range cases {
+		tt := cases[i]
+		t.Run(tt.layout, func(t *testing.T) {
+			t.Parallel()
+			actual := strftime.FormatLocale(t1, tt.layout, tt.locale)
+			if actual != tt.expected {
+				t.Errorf("Test layout `%s`: expected: %q; actual: %q", tt.layout, tt.expected, actual)
+			}
+
+			l, err := strftime.CompileLocale(tt.layout, tt.locale)
+			if err != nil {
+				t.Fatalf("CompileLocale(%q): unexpected error: %v", tt.layout, err)
+			}
+			if actual := l.Format(t1); actual != tt.expected {
+				t.Errorf("Test compiled layout `%s`: expected: %q; actual: %q", tt.layout, tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestFormatLocaleUpperCase(t *testing.T) {
+	march := time.Date(2018, time.March, 9, 13, 14, 15, 0, time.UTC)
+	const expected = "MÄRZ|MÄR"
+	if actual := strftime.FormatLocale(march, "%^B|%#b", strftime.LocaleDeDE); actual != expected {
+		t.Errorf("expected: %q; actual: %q", expected, actual)
+	}
+}
+
+func TestFormatLocaleEnUSNamesMatchDefault(t *testing.T) {
+	const layout = "%a %A %b %B %p %P"
+	for _, tm := range []time.Time{t1, t2, t3, t4} {
+		for m := 0; m < 12; m++ {
+			tm := tm.AddDate(0, m, 0)
+			expected := strftime.Format(tm, layout)
+			if actual := strftime.FormatLocale(tm, layout, strftime.LocaleEnUS); actual != expected {
+				t.Errorf("%v: expected: %q; actual: %q", tm, expected, actual)
+			}
+		}
+	}
+}
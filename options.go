@@ -0,0 +1,140 @@
+// Copyright 2018 Timon Wong. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package strftime
+
+import (
+	"time"
+	"unicode/utf8"
+)
+
+// UnknownPolicy selects what formatting does with a specifier it does not
+// recognize, such as "%J", or with a '%' at the end of the layout.
+type UnknownPolicy int
+
+// Policies for unknown specifiers.
+const (
+	UnknownKeep     UnknownPolicy = iota // copy the specifier through, as Format does
+	UnknownDrop                          // leave the specifier out
+	UnknownReplace                       // write Options.Placeholder instead
+	UnknownCallback                      // let Options.UnknownFunc append the result
+)
+
+// Options controls formatting beyond the layout itself. The zero value
+// formats exactly as Format does.
+type Options struct {
+	// Locale supplies the names and preferred representations, as for
+	// FormatLocale. A nil Locale selects English.
+	Locale *Locale
+
+	// Specifiers holds custom specifiers, tried before UnknownSpecifier
+	// applies.
+	Specifiers *Specifiers
+
+	// UnknownSpecifier is the policy for unknown specifiers.
+	UnknownSpecifier UnknownPolicy
+
+	// Placeholder replaces unknown specifiers under UnknownReplace.
+	Placeholder string
+
+	// UnknownFunc appends the result of an unknown specifier to b under
+	// UnknownCallback. spec is its conversion character, which may be any
+	// rune, or 0 for a '%' at the end of the layout; flags and field
+	// widths are not passed on. A nil UnknownFunc keeps the specifier.
+	UnknownFunc func(b []byte, spec rune, t time.Time) []byte
+}
+
+// Format is like FormatLocale but formats according to o.
+func (o *Options) Format(t time.Time, layout string) string {
+	const bufSize = 64
+	var b [bufSize]byte
+	buf := o.AppendFormat(b[:0], t, layout)
+	return string(buf)
+}
+
+// AppendFormat is like Format but appends the textual
+// representation to b and returns the extended buffer.
+func (o *Options) AppendFormat(b []byte, t time.Time, layout string) []byte {
+	var f fields
+	f.init(t, o.Locale)
+	f.opts = o
+
+	return f.appendLayout(b, layout)
+}
+
+// Compile is like CompileLocale but the returned Layout formats as
+// o.Format does. The options are captured, so later changes to o do not
+// affect the Layout.
+func (o *Options) Compile(layout string) (*Layout, error) {
+	return newLayout(layout, o.Locale, o), nil
+}
+
+// custom returns the custom specifier at the start of text, the layout
+// following a '%', along with the length of its name.
+func (f *fields) custom(text string) (SpecifierFunc, int) {
+	if f.opts == nil {
+		return nil, 0
+	}
+	return f.opts.Specifiers.lookup(text)
+}
+
+// appendUnknown appends the result of the unknown specifier spec,
+// as selected by the options.
+func (f *fields) appendUnknown(b []byte, spec string) []byte {
+	if f.opts == nil {
+		return append(b, spec...)
+	}
+	switch f.opts.UnknownSpecifier {
+	case UnknownDrop:
+		return b
+	case UnknownReplace:
+		return append(b, f.opts.Placeholder...)
+	case UnknownCallback:
+		if fn := f.opts.UnknownFunc; fn != nil {
+			p := scratchPool.Get().(*[]byte)
+			out := fn((*p)[:0], unknownChar(spec), f.t)
+			b = append(b, out...)
+			*p = out[:0]
+			scratchPool.Put(p)
+			return b
+		}
+	}
+	return append(b, spec...)
+}
+
+// unknownOp returns what a compiled layout does with the unknown
+// specifier spec: either literal text or a function appending the result.
+func (o *Options) unknownOp(spec string) (string, SpecifierFunc) {
+	if o == nil {
+		return spec, nil
+	}
+	switch o.UnknownSpecifier {
+	case UnknownDrop:
+		return "", nil
+	case UnknownReplace:
+		return o.Placeholder, nil
+	case UnknownCallback:
+		if fn := o.UnknownFunc; fn != nil {
+			c := unknownChar(spec)
+			return "", func(b []byte, t time.Time) []byte {
+				return fn(b, c, t)
+			}
+		}
+	}
+	return spec, nil
+}
+
+// unknownChar returns the conversion character of the unknown specifier
+// spec, past the '%' and any flags, width and colons, or 0 if there is none.
+func unknownChar(spec string) rune {
+	j, _ := stdFlags(spec, 1)
+	for j < len(spec) && spec[j] == ':' {
+		j++
+	}
+	if j < len(spec) {
+		c, _ := utf8.DecodeRuneInString(spec[j:])
+		return c
+	}
+	return 0
+}
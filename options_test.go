@@ -0,0 +1,60 @@
+// Copyright 2018 Timon Wong. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package strftime_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/imperfectgo/go-strftime"
+)
+
+func TestOptionsFormat(t *testing.T) {
+	// A vendor conversion: %J for the Unix time.
+	julian := func(b []byte, spec rune, t time.Time) []byte {
+		switch spec {
+		case 'J':
+			return append(b, strftime.Format(t, "%s")...)
+		case 0:
+			return append(b, "<end>"...)
+		}
+		return append(append(b, '?'), string(spec)...)
+	}
+	cases := []struct {
+		opts     *strftime.Options
+		layout   string
+		expected string
+	}{
+		{opts: &strftime.Options{}, layout: "%Y %J %-5J %", expected: "2018 %J %-5J %"},
+		{opts: &strftime.Options{UnknownSpecifier: strftime.UnknownKeep}, layout: "%é%Y", expected: "%é2018"},
+		{opts: &strftime.Options{UnknownSpecifier: strftime.UnknownDrop}, layout: "%Y %J %-5J %", expected: "2018   "},
+		{opts: &strftime.Options{UnknownSpecifier: strftime.UnknownReplace, Placeholder: "?"}, layout: "%Y %J %:%Y %", expected: "2018 ? ?2018 ?"},
+		{opts: &strftime.Options{UnknownSpecifier: strftime.UnknownReplace}, layout: "[%J]", expected: "[]"},
+		{opts: &strftime.Options{UnknownSpecifier: strftime.UnknownCallback, UnknownFunc: julian}, layout: "%J|%-3K|%::Y|%", expected: "1531142055|?K|?Y|<end>"},
+		{opts: &strftime.Options{UnknownSpecifier: strftime.UnknownCallback, UnknownFunc: julian}, layout: "%é|%-2日|%:ü", expected: "?é|?日|?ü"},
+		{opts: &strftime.Options{UnknownSpecifier: strftime.UnknownCallback}, layout: "%J", expected: "%J"},
+		{opts: &strftime.Options{Locale: strftime.LocaleDeDE, UnknownSpecifier: strftime.UnknownDrop}, layout: "%A%J %x", expected: "Montag 09.07.2018"},
+	}
+
+	for i := range cases {
+		tt := cases[i]
+		t.Run(tt.layout, func(t *testing.T) {
+			t.Parallel()
+			if actual := tt.opts.Format(t1, tt.layout); actual != tt.expected {
+				t.Errorf("Test layout `%s`: expected: %q; actual: %q", tt.layout, tt.expected, actual)
+			}
+			if actual := string(tt.opts.AppendFormat([]byte("x"), t1, tt.layout)); actual != "x"+tt.expected {
+				t.Errorf("Test layout `%s`: expected: %q; actual: %q", tt.layout, "x"+tt.expected, actual)
+			}
+			l, err := tt.opts.Compile(tt.layout)
+			if err != nil {
+				t.Fatalf("Compile(%q): unexpected error: %v", tt.layout, err)
+			}
+			if actual := l.Format(t1); actual != tt.expected {
+				t.Errorf("Test compiled layout `%s`: expected: %q; actual: %q", tt.layout, tt.expected, actual)
+			}
+		})
+	}
+}
@@ -0,0 +1,705 @@
+// Copyright 2018 Timon Wong. All rights reserved.
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package strftime
+
+import (
+	"errors"
+	"time"
+)
+
+// ParseError describes a problem parsing a time string.
+type ParseError struct {
+	Layout     string
+	Value      string
+	LayoutElem string
+	ValueElem  string
+	Message    string
+}
+
+// Error returns the string representation of a ParseError.
+func (e *ParseError) Error() string {
+	if e.Message == "" {
+		return "parsing time " + quote(e.Value) + " as " + quote(e.Layout) +
+			": cannot parse " + quote(e.ValueElem) + " as " + quote(e.LayoutElem)
+	}
+	return "parsing time " + quote(e.Value) + e.Message
+}
+
+var errBad = errors.New("bad value for field") // placeholder not passed to user
+
+// Parse parses a formatted string and returns the time value it represents.
+// The layout uses the same specifiers as Format, so any time formatted with
+// a layout can be read back with the same layout.
+//
+// Elements omitted from the value are assumed to be zero or, when zero is
+// impossible, one. When the value carries no date, the year defaults to 0,
+// just like time.Parse. When no month or day is given, the date is derived
+// from %j, from %G/%g and %V, or from %U/%W, in that order; the week-based
+// specifiers combine with the weekday given by %a, %A, %u or %w, which
+// otherwise defaults to the first day of the week, or to January 1 in week
+// 0 of %U/%W. %G/%g without %V stand for the first day of week 1.
+//
+// Textual specifiers (%a, %A, %b, %B, %p, %P) are matched case-insensitively
+// and accept both the abbreviated and the full names. %z accepts "Z",
+// "+hh", "+hhmm" and "+hh:mm".
+//
+// In the absence of a time zone indicator, Parse returns a time in UTC.
+// Time zones are resolved the same way as by time.Parse.
+func Parse(layout, value string) (time.Time, error) {
+	return parse(layout, value, time.UTC, time.Local)
+}
+
+// ParseInLocation is like Parse but differs in two important ways.
+// First, in the absence of time zone information, Parse interprets a time
+// as UTC; ParseInLocation interprets the time as in the given location.
+// Second, when given a zone offset or abbreviation, Parse tries to match it
+// against the Local location; ParseInLocation uses the given location.
+func ParseInLocation(layout, value string, loc *time.Location) (time.Time, error) {
+	return parse(layout, value, loc, loc)
+}
+
+func parse(layout, value string, defaultLocation, local *time.Location) (time.Time, error) {
+	alayout, avalue := layout, value
+	var (
+		century    = -1
+		year       = -1
+		shortYear  = -1
+		month      = -1
+		day        = -1
+		yday       = -1
+		hour       int
+		min        int
+		sec        int
+		nsec       int
+		pmSet      bool
+		amSet      bool
+		weekday    = -1
+		sunWeek    = -1
+		monWeek    = -1
+		isoYear    = -1
+		isoYear2   = -1
+		isoWeek    = -1
+		zoneOffset = -1
+		zoneName   string
+		unix       int64 // seconds since the epoch, valid if unixSet
+		unixNsec   int   // sub-second part of a %Q value
+		unixSet    bool
+
+		// Composite specifiers are parsed by pushing the rest of the
+		// layout and continuing with their expansion.
+		stack [2]string
+		depth int
+	)
+
+	// Each iteration processes one std value.
+	for {
+		var err error
+		prefix, std, suffix := nextStdChunk(layout)
+		if len(value) < len(prefix) || value[:len(prefix)] != prefix {
+			return time.Time{}, &ParseError{Layout: alayout, Value: avalue, LayoutElem: prefix, ValueElem: value}
+		}
+		value = value[len(prefix):]
+		if std == 0 {
+			if depth > 0 {
+				depth--
+				layout = stack[depth]
+				continue
+			}
+			if len(value) != 0 {
+				return time.Time{}, &ParseError{Layout: alayout, Value: avalue, Message: ": extra text: " + quote(value)}
+			}
+			break
+		}
+		stdstr := layout[len(prefix) : len(layout)-len(suffix)]
+		layout = suffix
+		hold := value
+		if std&stdWidth != 0 || std>>stdPadShift&3 == stdPadSpace {
+			value = skipPadding(value, std)
+		}
+		switch std & stdMask {
+		case stdNop:
+			continue
+		case stdUnknown:
+			// Copied through by Format, so matched literally.
+			if len(value) < len(stdstr) || value[:len(stdstr)] != stdstr {
+				err = errBad
+				break
+			}
+			value = value[len(stdstr):]
+		case stdComposite:
+			stack[depth] = layout
+			depth++
+			layout = compositeLayout(nil, std>>stdValueShift, depth > 1)
+			continue
+		case stdLongYear:
+			year, value, err = getnum(value, 4, 4)
+		case stdYear:
+			shortYear, value, err = getnum(value, 1, 2)
+		case stdFirstTwoDigitYear:
+			century, value, err = getnum(value, 1, 2)
+		case stdYearDay:
+			yday, value, err = getnum(value, 1, 3)
+			if err == nil && (yday < 1 || yday > 366) {
+				err = errBad
+			}
+		case stdISO8601LongWeekYear:
+			isoYear, value, err = getnum(value, 4, 4)
+		case stdISO8601WeekYear:
+			isoYear2, value, err = getnum(value, 1, 2)
+		case stdISO8601Week:
+			isoWeek, value, err = getnum(value, 1, 2)
+			if err == nil && (isoWeek < 1 || isoWeek > 53) {
+				err = errBad
+			}
+		case stdWeekOfYear:
+			sunWeek, value, err = getnum(value, 1, 2)
+			if err == nil && sunWeek > 53 {
+				err = errBad
+			}
+		case stdMonFirstWeekOfYear:
+			monWeek, value, err = getnum(value, 1, 2)
+			if err == nil && monWeek > 53 {
+				err = errBad
+			}
+		case stdMonth, stdLongMonth:
+			month, value, err = lookupMonth(value)
+		case stdNumMonth, stdZeroMonth:
+			month, value, err = getnum(value, 1, 2)
+			if err == nil && (month < 1 || month > 12) {
+				err = errBad
+			}
+		case stdWeekDay, stdLongWeekDay:
+			weekday, value, err = lookupWeekday(value)
+		case stdZeroBasedNumWeekDay:
+			weekday, value, err = getnum(value, 1, 1)
+			if err == nil && weekday > 6 {
+				err = errBad
+			}
+		case stdNumWeekDay:
+			weekday, value, err = getnum(value, 1, 1)
+			if err == nil && (weekday < 1 || weekday > 7) {
+				err = errBad
+			}
+			weekday %= 7
+		case stdUnderDay:
+			if len(value) > 0 && value[0] == ' ' {
+				value = value[1:]
+			}
+			fallthrough
+		case stdDay, stdZeroDay:
+			day, value, err = getnum(value, 1, 2)
+			// Validated later against the month.
+		case stdUnderHour:
+			if len(value) > 0 && value[0] == ' ' {
+				value = value[1:]
+			}
+			fallthrough
+		case stdHour:
+			hour, value, err = getnum(value, 1, 2)
+			if err == nil && hour > 23 {
+				err = errBad
+			}
+		case stdUnderHour12:
+			if len(value) > 0 && value[0] == ' ' {
+				value = value[1:]
+			}
+			fallthrough
+		case stdHour12, stdZeroHour12:
+			hour, value, err = getnum(value, 1, 2)
+			if err == nil && (hour < 1 || hour > 12) {
+				err = errBad
+			}
+		case stdMinute, stdZeroMinute:
+			min, value, err = getnum(value, 1, 2)
+			if err == nil && min > 59 {
+				err = errBad
+			}
+		case stdSecond, stdZeroSecond:
+			sec, value, err = getnum(value, 1, 2)
+			// Leap seconds are accepted and normalized, like strptime does.
+			if err == nil && sec > 60 {
+				err = errBad
+			}
+		case stdPM, stdpm:
+			if len(value) < 2 {
+				err = errBad
+				break
+			}
+			switch p := value[:2]; {
+			case match(p, "PM"):
+				pmSet = true
+			case match(p, "AM"):
+				amSet = true
+			default:
+				err = errBad
+			}
+			value = value[2:]
+		case stdFracSecond0, stdFracSecond9:
+			nsec, value, err = getfrac(value, std>>stdValueShift)
+		case stdUnix:
+			unix, value, err = getint64(value)
+			unixNsec, unixSet = 0, true
+		case stdUnixMilli:
+			var ms int64
+			ms, value, err = getint64(value)
+			// Round down, so the sub-second part is never negative.
+			unix, unixNsec, unixSet = ms/1e3, int(ms%1e3)*1e6, true
+			if unixNsec < 0 {
+				unix, unixNsec = unix-1, unixNsec+1e9
+			}
+		case stdNumTZ, stdISO8601TZ:
+			zoneOffset, value, err = getoffset(value)
+		case stdTZ:
+			n := 0
+			for n < len(value) && isAlpha(value[n]) {
+				n++
+			}
+			if n < 3 {
+				err = errBad
+				break
+			}
+			zoneName, value = value[:n], value[n:]
+		}
+		if err != nil {
+			return time.Time{}, &ParseError{Layout: alayout, Value: avalue, LayoutElem: stdstr, ValueElem: hold}
+		}
+	}
+
+	if unixSet {
+		// The epoch time fixes the instant; a %f or %N value refines
+		// the sub-second part of a %s one.
+		if nsec != 0 {
+			unixNsec = nsec
+		}
+		t := time.Unix(unix, int64(unixNsec))
+		if zoneOffset != -1 {
+			return t.In(time.FixedZone(zoneName, zoneOffset)), nil
+		}
+		return t.In(defaultLocation), nil
+	}
+
+	switch {
+	case year >= 0:
+	case shortYear >= 0 && century >= 0:
+		year = century*100 + shortYear
+	case shortYear >= 0:
+		year = shortYear + 1900
+		if shortYear < 69 {
+			year += 100
+		}
+	case century >= 0:
+		year = century * 100
+	case isoYear >= 0, isoYear2 >= 0:
+	default:
+		year = 0
+	}
+	if isoYear < 0 && isoYear2 >= 0 {
+		if century >= 0 {
+			isoYear = century*100 + isoYear2
+		} else {
+			isoYear = isoYear2 + 1900
+			if isoYear2 < 69 {
+				isoYear += 100
+			}
+		}
+	}
+
+	if isoWeek < 0 && isoYear >= 0 && year < 0 {
+		isoWeek = 1
+	}
+
+	var date time.Time
+	switch {
+	case month >= 0 || day >= 0:
+		if month < 0 {
+			month = 1
+		}
+		if day < 0 {
+			day = 1
+		}
+		if year < 0 {
+			year = isoYear
+		}
+		// Validate the day of the month.
+		if day < 1 || day > daysIn(time.Month(month), year) {
+			return time.Time{}, &ParseError{Layout: alayout, Value: avalue, Message: ": day out of range"}
+		}
+		date = time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+	case yday >= 0 && year >= 0:
+		if yday > 365 && !isLeap(year) {
+			return time.Time{}, &ParseError{Layout: alayout, Value: avalue, Message: ": day-of-year out of range"}
+		}
+		date = time.Date(year, time.January, yday, 0, 0, 0, 0, time.UTC)
+	case isoWeek >= 0 && isoYear >= 0:
+		// ISO 8601 week 1 is the week containing January 4th.
+		jan4 := time.Date(isoYear, time.January, 4, 0, 0, 0, 0, time.UTC)
+		wd := time.Monday
+		if weekday >= 0 {
+			wd = time.Weekday(weekday)
+		}
+		date = jan4.AddDate(0, 0, (isoWeek-1)*7+monFirst(wd)-monFirst(jan4.Weekday()))
+		if y, w := date.ISOWeek(); y != isoYear || w != isoWeek {
+			return time.Time{}, &ParseError{Layout: alayout, Value: avalue, Message: ": week out of range"}
+		}
+	case (sunWeek >= 0 || monWeek >= 0) && year >= 0:
+		jan1 := time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
+		var n int
+		if sunWeek >= 0 {
+			// Week 1 starts on the first Sunday of the year.
+			wd := time.Sunday
+			if weekday >= 0 {
+				wd = time.Weekday(weekday)
+			}
+			n = (7-int(jan1.Weekday()))%7 + (sunWeek-1)*7 + int(wd)
+		} else {
+			// Week 1 starts on the first Monday of the year.
+			wd := time.Monday
+			if weekday >= 0 {
+				wd = time.Weekday(weekday)
+			}
+			n = (8-int(jan1.Weekday()))%7 + (monWeek-1)*7 + monFirst(wd)
+		}
+		if weekday < 0 && n < 0 && n > -7 {
+			// Week 0 starts on January 1.
+			n = 0
+		}
+		date = jan1.AddDate(0, 0, n)
+		if date.Year() != year {
+			return time.Time{}, &ParseError{Layout: alayout, Value: avalue, Message: ": week out of range"}
+		}
+	default:
+		if year < 0 {
+			year = 0
+		}
+		date = time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
+	}
+	year, mon, day := date.Date()
+
+	if pmSet && hour < 12 {
+		hour += 12
+	} else if amSet && hour == 12 {
+		hour = 0
+	}
+
+	if zoneOffset != -1 {
+		t := time.Date(year, mon, day, hour, min, sec, nsec, time.UTC)
+		t = t.Add(time.Duration(-zoneOffset) * time.Second)
+
+		// Look for local zone with the given offset.
+		// If that zone was in effect at the given time, use it.
+		lt := t.In(local)
+		name, offset := lt.Zone()
+		if offset == zoneOffset && (zoneName == "" || name == zoneName) {
+			return lt, nil
+		}
+
+		// Otherwise create fake zone to record offset.
+		return t.In(time.FixedZone(zoneName, zoneOffset)), nil
+	}
+
+	if zoneName != "" {
+		if zoneName == "UTC" {
+			return time.Date(year, mon, day, hour, min, sec, nsec, time.UTC), nil
+		}
+
+		// Look up the zone abbreviation in the local location.
+		lt := time.Date(year, mon, day, hour, min, sec, nsec, local)
+		if name, _ := lt.Zone(); name == zoneName {
+			return lt, nil
+		}
+
+		// A wall time repeated by a transition may belong to the zone on
+		// the other side of it.
+		for _, d := range [...]time.Duration{-24 * time.Hour, 24 * time.Hour} {
+			if name, offset := lt.Add(d).Zone(); name == zoneName {
+				t := time.Date(year, mon, day, hour, min, sec, nsec, time.UTC)
+				t = t.Add(time.Duration(-offset) * time.Second).In(local)
+				if name, _ := t.Zone(); name == zoneName {
+					return t, nil
+				}
+			}
+		}
+
+		// Otherwise, create fake zone with unknown offset.
+		t := time.Date(year, mon, day, hour, min, sec, nsec, time.UTC)
+		return t.In(time.FixedZone(zoneName, 0)), nil
+	}
+
+	return time.Date(year, mon, day, hour, min, sec, nsec, defaultLocation), nil
+}
+
+// skipPadding skips the padding added to the std value at the beginning
+// of s by a GNU flag or a field width: leading spaces and, beyond the
+// digits the value has by default, leading zeros.
+func skipPadding(s string, std int) string {
+	switch std & stdMask {
+	case stdFracSecond0, stdFracSecond9, stdNumTZ, stdISO8601TZ:
+		return s
+	}
+	for len(s) > 0 && s[0] == ' ' {
+		s = s[1:]
+	}
+	digits := stdDigits(std)
+	n := 0
+	for n < len(s) && isDigit(s[n]) {
+		n++
+	}
+	for n > digits && s[0] == '0' {
+		s = s[1:]
+		n--
+	}
+	return s
+}
+
+// stdDigits returns the number of digits the numeric std value has by
+// default, or 0 for a textual one.
+func stdDigits(std int) int {
+	switch std & stdMask {
+	case stdMonth, stdLongMonth, stdWeekDay, stdLongWeekDay, stdPM, stdpm, stdTZ:
+		return 0
+	case stdLongYear, stdISO8601LongWeekYear:
+		return 4
+	case stdYearDay:
+		return 3
+	case stdZeroBasedNumWeekDay, stdNumWeekDay, stdUnix, stdUnixMilli:
+		return 1
+	}
+	return 2
+}
+
+// getnum parses a decimal number of at least minDigits and at most
+// maxDigits digits from the beginning of s.
+func getnum(s string, minDigits, maxDigits int) (int, string, error) {
+	x, n := 0, 0
+	for n < len(s) && n < maxDigits && isDigit(s[n]) {
+		x = x*10 + int(s[n]-'0')
+		n++
+	}
+	if n < minDigits {
+		return 0, s, errBad
+	}
+	return x, s[n:], nil
+}
+
+// getint64 parses an optionally signed decimal number from the
+// beginning of s.
+func getint64(s string) (int64, string, error) {
+	neg := len(s) > 0 && s[0] == '-'
+	if neg || len(s) > 0 && s[0] == '+' {
+		s = s[1:]
+	}
+	var x uint64
+	n := 0
+	for n < len(s) && isDigit(s[n]) {
+		if x > (1<<63)/10 {
+			return 0, s, errBad
+		}
+		x = x*10 + uint64(s[n]-'0')
+		n++
+	}
+	if n == 0 || x > 1<<63 || !neg && x == 1<<63 {
+		return 0, s, errBad
+	}
+	if neg {
+		return -int64(x), s[n:], nil
+	}
+	return int64(x), s[n:], nil
+}
+
+// getfrac parses up to digits fractional second digits from the beginning
+// of s and returns them as nanoseconds.
+func getfrac(s string, digits int) (int, string, error) {
+	if digits > 9 {
+		digits = 9
+	}
+	ns, n := 0, 0
+	for n < len(s) && n < digits && isDigit(s[n]) {
+		ns = ns*10 + int(s[n]-'0')
+		n++
+	}
+	if n == 0 {
+		return 0, s, errBad
+	}
+	for i := n; i < 9; i++ {
+		ns *= 10
+	}
+	return ns, s[n:], nil
+}
+
+// getoffset parses a numeric time zone offset of the form "Z", "+hh",
+// "+hhmm", "+hh:mm" or "+hh:mm:ss" and returns it in seconds east of UTC.
+func getoffset(s string) (int, string, error) {
+	if len(s) > 0 && (s[0] == 'Z' || s[0] == 'z') {
+		return 0, s[1:], nil
+	}
+	if len(s) < 3 || (s[0] != '+' && s[0] != '-') {
+		return 0, s, errBad
+	}
+	sign := s[0]
+	hh, rest, err := getnum(s[1:], 2, 2)
+	if err != nil {
+		return 0, s, err
+	}
+	mm, ss := 0, 0
+	if len(rest) > 0 && rest[0] == ':' {
+		if mm, rest, err = getnum(rest[1:], 2, 2); err != nil {
+			return 0, s, err
+		}
+		if len(rest) > 2 && rest[0] == ':' && isDigit(rest[1]) {
+			if ss, rest, err = getnum(rest[1:], 2, 2); err != nil {
+				return 0, s, err
+			}
+		}
+	} else if len(rest) > 1 && isDigit(rest[0]) && isDigit(rest[1]) {
+		mm, rest, _ = getnum(rest, 2, 2)
+	}
+	if hh > 24 || mm > 59 || ss > 59 {
+		return 0, s, errBad
+	}
+	offset := (hh*60+mm)*60 + ss
+	if sign == '-' {
+		offset = -offset
+	}
+	return offset, rest, nil
+}
+
+// lookupMonth matches a full or abbreviated English month name
+// at the beginning of s and returns the month number.
+func lookupMonth(s string) (int, string, error) {
+	for m := time.January; m <= time.December; m++ {
+		if name := m.String(); len(s) >= len(name) && match(s[:len(name)], name) {
+			return int(m), s[len(name):], nil
+		}
+	}
+	for m := time.January; m <= time.December; m++ {
+		if name := m.String()[:3]; len(s) >= 3 && match(s[:3], name) {
+			return int(m), s[3:], nil
+		}
+	}
+	return -1, s, errBad
+}
+
+// lookupWeekday matches a full or abbreviated English weekday name
+// at the beginning of s and returns the weekday number (Sunday is 0).
+func lookupWeekday(s string) (int, string, error) {
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		if name := d.String(); len(s) >= len(name) && match(s[:len(name)], name) {
+			return int(d), s[len(name):], nil
+		}
+	}
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		if name := d.String()[:3]; len(s) >= 3 && match(s[:3], name) {
+			return int(d), s[3:], nil
+		}
+	}
+	return -1, s, errBad
+}
+
+// match reports whether s1 and s2 match ignoring case.
+// It is assumed s1 and s2 are the same length.
+func match(s1, s2 string) bool {
+	for i := 0; i < len(s1); i++ {
+		c1 := s1[i]
+		c2 := s2[i]
+		if c1 != c2 {
+			// Switch to lower-case; 'a'-'A' is known to be a single bit.
+			c1 |= 'a' - 'A'
+			c2 |= 'a' - 'A'
+			if c1 != c2 || c1 < 'a' || c1 > 'z' {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// monFirst converts a weekday to a Monday-based index (Monday is 0).
+func monFirst(wd time.Weekday) int {
+	return (int(wd) + 6) % 7
+}
+
+func isDigit(c byte) bool {
+	return '0' <= c && c <= '9'
+}
+
+func isAlpha(c byte) bool {
+	c |= 'a' - 'A'
+	return 'a' <= c && c <= 'z'
+}
+
+func isLeap(year int) bool {
+	return year%4 == 0 && (year%100 != 0 || year%400 == 0)
+}
+
+func daysIn(m time.Month, year int) int {
+	if m == time.February && isLeap(year) {
+		return 29
+	}
+	return int(daysBefore[m] - daysBefore[m-1])
+}
+
+// daysBefore[m] counts the number of days in a non-leap year
+// before month m begins. There is an entry for m=12, counting
+// the number of days before January of next year (365).
+var daysBefore = [...]int32{
+	0,
+	31,
+	31 + 28,
+	31 + 28 + 31,
+	31 + 28 + 31 + 30,
+	31 + 28 + 31 + 30 + 31,
+	31 + 28 + 31 + 30 + 31 + 30,
+	31 + 28 + 31 + 30 + 31 + 30 + 31,
+	31 + 28 + 31 + 30 + 31 + 30 + 31 + 31,
+	31 + 28 + 31 + 30 + 31 + 30 + 31 + 31 + 30,
+	31 + 28 + 31 + 30 + 31 + 30 + 31 + 31 + 30 + 31,
+	31 + 28 + 31 + 30 + 31 + 30 + 31 + 31 + 30 + 31 + 30,
+	31 + 28 + 31 + 30 + 31 + 30 + 31 + 31 + 30 + 31 + 30 + 31,
+}
+
+const (
+	lowerhex  = "0123456789abcdef"
+	runeSelf  = 0x80
+	runeError = '�'
+)
+
+// quote is like strconv.Quote but avoids the dependency.
+// Duplicated from the standard Go library.
+func quote(s string) string {
+	buf := make([]byte, 1, len(s)+2) // slice will be at least len(s) + quotes
+	buf[0] = '"'
+	for i, c := range s {
+		if c >= runeSelf || c < ' ' {
+			// This means you are asking us to parse a time.Duration or
+			// time.Location with unprintable or non-ASCII characters in it.
+			// We don't expect to hit this case very often. We could try to
+			// reproduce strconv.Quote's behavior with full fidelity but
+			// given how rarely we expect to hit these edge cases, speed and
+			// conciseness are better.
+			var width int
+			if c == runeError {
+				width = 1
+				if i+2 < len(s) && s[i:i+3] == string(runeError) {
+					width = 3
+				}
+			} else {
+				width = len(string(c))
+			}
+			for j := 0; j < width; j++ {
+				buf = append(buf, `\x`...)
+				buf = append(buf, lowerhex[s[i+j]>>4])
+				buf = append(buf, lowerhex[s[i+j]&0xF])
+			}
+		} else {
+			if c == '"' || c == '\\' {
+				buf = append(buf, '\\')
+			}
+			buf = append(buf, string(c)...)
+		}
+	}
+	buf = append(buf, '"')
+	return string(buf)
+}
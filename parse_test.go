@@ -0,0 +1,204 @@
+// Copyright 2018 Timon Wong. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package strftime_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/imperfectgo/go-strftime"
+)
+
+func TestParse(t *testing.T) {
+	est := time.FixedZone("", -5*3600)
+	cases := []struct {
+		layout   string
+		value    string
+		expected time.Time
+	}{
+		{layout: "%Y-%m-%d %H:%M:%S %z", value: "2018-07-09 13:14:15 +0000", expected: t1},
+		{layout: "%Y-%m-%d %H:%M:%S %z", value: "2018-07-09 08:14:15 -0500", expected: t1.In(est)},
+		{layout: "%Y-%m-%d %H:%M:%S %z", value: "2018-07-09 08:14:15 -05:00", expected: t1.In(est)},
+		{layout: "%Y-%m-%dT%H:%M:%S%z", value: "2018-07-09T13:14:15Z", expected: t1},
+		{layout: "%c", value: "Mon Jul  9 13:14:15 2018", expected: t1},
+		{layout: "%c", value: "Sun Dec 10 04:45:59 1950", expected: t2},
+		{layout: "%a, %d %b %Y %T %Z", value: "Mon, 09 Jul 2018 13:14:15 UTC", expected: t1},
+		{layout: "%A %B %e %Y %r", value: "monday JULY  9 2018 01:14:15 PM", expected: t1},
+		{layout: "%F %I:%M:%S %P", value: "1950-12-10 04:45:59 am", expected: t2},
+		{layout: "%D %R:%S", value: "07/09/18 13:14:15", expected: t1},
+		{layout: "%x %X", value: "12/10/1950 04:45:59", expected: t2},
+		{layout: "%C%y-%m-%d", value: "1950-12-10", expected: time.Date(1950, time.December, 10, 0, 0, 0, 0, time.UTC)},
+		{layout: "%y%m%d", value: "680101", expected: time.Date(2068, time.January, 1, 0, 0, 0, 0, time.UTC)},
+		{layout: "%y%m%d", value: "690101", expected: time.Date(1969, time.January, 1, 0, 0, 0, 0, time.UTC)},
+		{layout: "%Y-%j", value: "2018-190", expected: time.Date(2018, time.July, 9, 0, 0, 0, 0, time.UTC)},
+		{layout: "%G-W%V-%u", value: "2018-W28-1", expected: time.Date(2018, time.July, 9, 0, 0, 0, 0, time.UTC)},
+		{layout: "%G-W%V", value: "2015-W53", expected: time.Date(2015, time.December, 28, 0, 0, 0, 0, time.UTC)},
+		{layout: "%g%V%a", value: "1601Sun", expected: time.Date(2016, time.January, 10, 0, 0, 0, 0, time.UTC)},
+		{layout: "%Y %U %w", value: "2018 27 1", expected: time.Date(2018, time.July, 9, 0, 0, 0, 0, time.UTC)},
+		{layout: "%Y %W %a", value: "2018 28 Mon", expected: time.Date(2018, time.July, 9, 0, 0, 0, 0, time.UTC)},
+		{layout: "%Y %U", value: "2017 01", expected: time.Date(2017, time.January, 1, 0, 0, 0, 0, time.UTC)},
+		{layout: "%Y %W", value: "2018 01", expected: time.Date(2018, time.January, 1, 0, 0, 0, 0, time.UTC)},
+		{layout: "%Y %U", value: "2021 00", expected: time.Date(2021, time.January, 1, 0, 0, 0, 0, time.UTC)},
+		{layout: "%Y %W", value: "2021 00", expected: time.Date(2021, time.January, 1, 0, 0, 0, 0, time.UTC)},
+		{layout: "%G", value: "2021", expected: time.Date(2021, time.January, 4, 0, 0, 0, 0, time.UTC)},
+		{layout: "%C", value: "20", expected: time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC)},
+		{layout: "%H:%M:%S.%f", value: "13:14:15.123456", expected: time.Date(0, time.January, 1, 13, 14, 15, 123456000, time.UTC)},
+		{layout: "%H:%M:%S.%f", value: "13:14:15.5", expected: time.Date(0, time.January, 1, 13, 14, 15, 500000000, time.UTC)},
+		{layout: "%H:%M:%S.%9f", value: "13:14:15.123456789", expected: time.Date(0, time.January, 1, 13, 14, 15, 123456789, time.UTC)},
+		{layout: "%d/%m/%Y", value: "9/7/2018", expected: time.Date(2018, time.July, 9, 0, 0, 0, 0, time.UTC)},
+		{layout: "%b %Y", value: "Feb 2016", expected: time.Date(2016, time.February, 1, 0, 0, 0, 0, time.UTC)},
+		{layout: "100%% %Y%n%t", value: "100% 2018\n\t", expected: time.Date(2018, time.January, 1, 0, 0, 0, 0, time.UTC)},
+		{layout: "%I %p", value: "12 AM", expected: time.Date(0, time.January, 1, 0, 0, 0, 0, time.UTC)},
+		{layout: "%I %p", value: "12 PM", expected: time.Date(0, time.January, 1, 12, 0, 0, 0, time.UTC)},
+		{layout: "%-m/%-d/%y %-I:%-M:%-S %^p", value: "7/9/18 1:14:15 PM", expected: t1},
+		{layout: "%^a %_d %^b %Y %_H:%M:%S", value: "MON  9 JUL 2018 13:14:15", expected: t1},
+		{layout: "%F %_H:%M:%S", value: "1950-12-10  4:45:59", expected: t2},
+		{layout: "%10A %_4d %010B %6Y %3H:%-4M:%S", value: "    Monday    9 000000July 002018 013:  14:15", expected: t1},
+		{layout: "%s", value: "1531142055", expected: t1},
+		{layout: "%s", value: "-601499641", expected: t2},
+		{layout: "%s.%N", value: "1531142055.123456789", expected: time.Unix(1531142055, 123456789)},
+		{layout: "%Q", value: "1531142055123", expected: time.Unix(1531142055, 123000000)},
+		{layout: "%Q", value: "-750", expected: time.Unix(-1, 250000000)},
+		{layout: "%s %z", value: "1531142055 -0500", expected: t1.In(est)},
+		{layout: "%FT%T%#:z", value: "2018-07-09T13:14:15Z", expected: t1},
+		{layout: "%FT%T%:z", value: "2018-07-09T08:14:15-05:00", expected: t1.In(est)},
+		{layout: "%F %T %::z", value: "2018-07-09 08:18:13 -04:56:02", expected: t1.In(time.FixedZone("", -(4*3600 + 56*60 + 2)))},
+		{layout: "%F %T %:::z", value: "2018-07-09 08:14:15 -05", expected: t1.In(est)},
+		{layout: "%F %k:%M:%S", value: "1950-12-10  4:45:59", expected: t2},
+		{layout: "%F %l:%M:%S %p", value: "2018-07-09  1:14:15 PM", expected: t1},
+		{layout: "%J %Y%", value: "%J 2018%", expected: time.Date(2018, time.January, 1, 0, 0, 0, 0, time.UTC)},
+		{layout: "%l:%M %p", value: "12:05 AM", expected: time.Date(0, time.January, 1, 0, 5, 0, 0, time.UTC)},
+	}
+
+	for i := range cases {
+		tt := cases[i]
+		t.Run(tt.layout, func(t *testing.T) {
+			t.Parallel()
+			actual, err := strftime.Parse(tt.layout, tt.value)
+			if err != nil {
+				t.Fatalf("Parse(%q, %q): unexpected error: %v", tt.layout, tt.value, err)
+			}
+			if !actual.Equal(tt.expected) {
+				t.Errorf("Parse(%q, %q): expected: %v; actual: %v", tt.layout, tt.value, tt.expected, actual)
+			}
+			_, expectedOffset := tt.expected.Zone()
+			if _, offset := actual.Zone(); offset != expectedOffset {
+				t.Errorf("Parse(%q, %q): expected offset: %d; actual: %d", tt.layout, tt.value, expectedOffset, offset)
+			}
+		})
+	}
+}
+
+func TestParseRoundTrip(t *testing.T) {
+	layouts := []string{
+		"%c",
+		"%a %d %b %Y %T %z",
+		"%A, %B %e %Y %I:%M:%S.%f %p %Z",
+		"%Y-%m-%dT%H:%M:%S.%f%z",
+		"%x %r",
+		"%C%y%j %R:%S",
+		"%G-W%V-%u %T",
+		"%C%g%V%a %T",
+		"%Y %U %w %T",
+		"%Y %W %A %T",
+		"%Y %5U %w %T",
+		"%Y %_3W %A %T",
+		"%F %l:%M:%S %p|%k",
+	}
+
+	for _, layout := range layouts {
+		layout := layout
+		t.Run(layout, func(t *testing.T) {
+			t.Parallel()
+			for _, tm := range []time.Time{t1, t2, t3, t4} {
+				value := strftime.Format(tm, layout)
+				parsed, err := strftime.Parse(layout, value)
+				if err != nil {
+					t.Fatalf("Parse(%q, %q): unexpected error: %v", layout, value, err)
+				}
+				if !parsed.Equal(tm) {
+					t.Errorf("Parse(%q, %q): expected: %v; actual: %v", layout, value, tm, parsed)
+				}
+			}
+		})
+	}
+}
+
+func TestParseInLocation(t *testing.T) {
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip("time zone database unavailable:", err)
+	}
+
+	actual, err := strftime.ParseInLocation("%F %T", "2018-07-09 09:14:15", loc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !actual.Equal(t1) || actual.Location() != loc {
+		t.Errorf("expected: %v; actual: %v", t1.In(loc), actual)
+	}
+
+	actual, err = strftime.ParseInLocation("%F %T %Z", "2018-07-09 09:14:15 EDT", loc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !actual.Equal(t1) || actual.Location() != loc {
+		t.Errorf("expected: %v; actual: %v", t1.In(loc), actual)
+	}
+
+	actual, err = strftime.ParseInLocation("%F %T %z", "2018-07-09 09:14:15 -0400", loc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !actual.Equal(t1) || actual.Location() != loc {
+		t.Errorf("expected: %v; actual: %v", t1.In(loc), actual)
+	}
+
+	// 01:30 occurs twice on 2018-11-04, first in EDT, then in EST.
+	for _, value := range []string{"2018-11-04 01:30:00 EDT", "2018-11-04 01:30:00 EST"} {
+		actual, err = strftime.ParseInLocation("%F %T %Z", value, loc)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if s := strftime.Format(actual, "%F %T %Z"); s != value || actual.Location() != loc {
+			t.Errorf("expected: %v; actual: %v", value, actual)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	cases := []struct {
+		layout string
+		value  string
+		errMsg string
+	}{
+		{layout: "%Y-%m-%d", value: "2018-07", errMsg: `parsing time "2018-07" as "%Y-%m-%d": cannot parse "" as "-"`},
+		{layout: "%Y-%m-%d", value: "2018-13-01", errMsg: `parsing time "2018-13-01" as "%Y-%m-%d": cannot parse "13-01" as "%m"`},
+		{layout: "%Y-%m-%d", value: "2018-02-30", errMsg: `parsing time "2018-02-30": day out of range`},
+		{layout: "%Y-%m-%d", value: "2018-07-09 extra", errMsg: `parsing time "2018-07-09 extra": extra text: " extra"`},
+		{layout: "%a %b", value: "Foo Jul", errMsg: `parsing time "Foo Jul" as "%a %b": cannot parse "Foo Jul" as "%a"`},
+		{layout: "%H:%M", value: "24:00", errMsg: `parsing time "24:00" as "%H:%M": cannot parse "24:00" as "%H"`},
+		{layout: "%Y-%j", value: "2018-366", errMsg: `parsing time "2018-366": day-of-year out of range`},
+		{layout: "%G-W%V", value: "2018-W53", errMsg: `parsing time "2018-W53": week out of range`},
+		{layout: "%Y %U", value: "2023 00", errMsg: `parsing time "2023 00": week out of range`},
+		{layout: "%z", value: "+7", errMsg: `parsing time "+7" as "%z": cannot parse "+7" as "%z"`},
+		{layout: "%::z", value: "+05:30:60", errMsg: `parsing time "+05:30:60" as "%::z": cannot parse "+05:30:60" as "%::z"`},
+		{layout: "%s", value: "9223372036854775808", errMsg: `parsing time "9223372036854775808" as "%s": cannot parse "9223372036854775808" as "%s"`},
+	}
+
+	for i := range cases {
+		tt := cases[i]
+		t.Run(tt.layout, func(t *testing.T) {
+			t.Parallel()
+			_, err := strftime.Parse(tt.layout, tt.value)
+			if err == nil {
+				t.Fatalf("Parse(%q, %q): expected error", tt.layout, tt.value)
+			}
+			if err.Error() != tt.errMsg {
+				t.Errorf("Parse(%q, %q): expected error: %s; actual: %s", tt.layout, tt.value, tt.errMsg, err)
+			}
+		})
+	}
+}
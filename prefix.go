@@ -0,0 +1,325 @@
+// Copyright 2018 Timon Wong. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package strftime
+
+import "time"
+
+// PrefixCover returns the smallest set of prefixes of the outputs of layout
+// such that an output of ExpandAll(layout, from, to) starts with exactly one
+// of them, and no other output of layout starts with any. For hierarchical
+// keys it trades most of the outputs for coarser prefixes:
+//
+//	// "events/2024/01/31/23/", "events/2024/02/", "events/2024/03/01/00/"
+//	strftime.PrefixCover("events/%Y/%m/%d/%H/", from, to)
+//
+// The prefixes are those of the layout that end after a specifier and the
+// literal text up to the next one, where the specifiers so far name a single
+// span of time, such as a year, a month of a year or a day of a month of a
+// year, and refine the previous prefix. Day of the year, %U, %W and ISO
+// 8601 weeks form such spans as well, %y is taken as a year and %s as a
+// second. Specifiers whose output varies in width must be followed by a
+// separator. Prefixes stop at a zone specifier. A layout without such
+// prefixes is covered by its outputs, as from ExpandAll.
+//
+// The same holds for the layout itself: one ending in a specifier whose
+// output varies in width, such as "%Y/%-m/%-d/%-H", where "2018/3/2/1" is
+// the start of "2018/3/2/10", is covered by its longest prefixes instead,
+// which may also cover outputs just outside the range. Without any such
+// prefix, as for "%s", the cover is the outputs themselves, as from
+// ExpandAll, of which one may be the start of another.
+//
+// Prefixes are returned in chronological order.
+func PrefixCover(layout string, from, to time.Time) []string {
+	if !from.Before(to) {
+		return nil
+	}
+
+	levels := prefixLevels(layout)
+	if len(levels) == 0 {
+		return ExpandAll(layout, from, to)
+	}
+	c := coverer{
+		layouts: levels,
+		g:       make([]granularity, len(levels)),
+		seen:    make(map[string]struct{}),
+	}
+	for i, l := range levels {
+		c.g[i] = layoutGranularity(l)
+	}
+
+	// Extend the range to the outputs of layout it overlaps.
+	last := len(levels) - 1
+	a, b := from, to
+	if f := c.g[last].floor(from, levels[last]); !f.IsZero() {
+		a = f
+	}
+	if n := c.g[last].next(to.Add(-1), levels[last]); !n.IsZero() {
+		b = n
+	}
+	c.cover(0, a, b)
+	return c.prefixes
+}
+
+// coverer collects the prefixes of PrefixCover.
+type coverer struct {
+	layouts  []string      // prefix layouts, from the shortest
+	g        []granularity // of layouts
+	seen     map[string]struct{}
+	prefixes []string
+}
+
+// cover adds the prefixes covering the times from a up to b at level k or
+// finer.
+func (c *coverer) cover(k int, a, b time.Time) {
+	layout, g := c.layouts[k], &c.g[k]
+	for t := a; t.Before(b); {
+		floor, next := g.floor(t, layout), g.next(t, layout)
+		if k == len(c.layouts)-1 || !floor.Before(a) && !next.IsZero() && !next.After(b) {
+			c.add(Format(t, layout))
+		} else {
+			lo, hi := a, b
+			if floor.After(lo) {
+				lo = floor
+			}
+			if !next.IsZero() && next.Before(hi) {
+				hi = next
+			}
+			c.cover(k+1, lo, hi)
+		}
+		if next.IsZero() {
+			return
+		}
+		t = next
+	}
+}
+
+func (c *coverer) add(prefix string) {
+	if _, ok := c.seen[prefix]; !ok {
+		c.seen[prefix] = struct{}{}
+		c.prefixes = append(c.prefixes, prefix)
+	}
+}
+
+// Fields known from the specifiers of a prefix, as bits for spanRank.
+const (
+	knowCentury = 1 << iota
+	knowYear
+	knowMonth
+	knowDay
+	knowYearDay
+	knowWeekSun
+	knowWeekMon
+	knowISOYear
+	knowISOWeek
+	knowWeekDay
+	knowHalfDay
+	knowHour12
+	knowHour
+	knowMinute
+	knowSecond
+	knowUnix
+)
+
+// Spans of time named by known fields, from the coarsest; a fraction of a
+// second of n digits ranks rankSecond+n.
+const (
+	rankNone = iota
+	rankCentury
+	rankYear
+	rankMonth
+	rankWeek
+	rankDay
+	rankHalfDay
+	rankHour
+	rankMinute
+	rankSecond
+)
+
+// prefixLevels returns the prefixes of layout that PrefixCover may use,
+// from the shortest, followed by layout itself unless an output of it may
+// be the start of another.
+func prefixLevels(layout string) []string {
+	var (
+		levels []string
+		known  int
+		digits int
+		rank   int
+	)
+	for rest := layout; rest != ""; {
+		_, std, suffix := nextStdChunk(rest)
+		if std == 0 {
+			break
+		}
+		rest = suffix
+		var ok bool
+		if known, digits, ok = knownFields(known, digits, std); !ok {
+			break
+		}
+		r := spanRank(known, digits)
+		if r <= rank {
+			continue
+		}
+		rank = r
+		sep, _, _ := nextStdChunk(suffix)
+		if end := len(layout) - len(suffix) + len(sep); end < len(layout) && (fixedWidth(std) || isSeparator(sep)) {
+			levels = append(levels, layout[:end])
+		}
+	}
+	if ownPrefix(layout) {
+		levels = append(levels, layout)
+	}
+	return levels
+}
+
+// ownPrefix reports whether no output of layout is the start of another:
+// its last specifier is of fixed width or followed by a separator.
+func ownPrefix(layout string) bool {
+	var last int
+	var sep string
+	for rest := layout; rest != ""; {
+		prefix, std, suffix := nextStdChunk(rest)
+		sep += prefix
+		if std == 0 {
+			break
+		}
+		switch std {
+		case stdNop:
+		case stdUnknown:
+			sep += rest[len(prefix) : len(rest)-len(suffix)]
+		default:
+			last, sep = std, ""
+		}
+		rest = suffix
+	}
+	return last == 0 || fixedWidth(last) || isSeparator(sep)
+}
+
+// knownFields adds the fields std shows to known, along with the digits of
+// fractional seconds. It reports false for zone specifiers.
+func knownFields(known, digits, std int) (int, int, bool) {
+	switch std & stdMask {
+	case stdLongYear, stdYear:
+		known |= knowYear
+	case stdFirstTwoDigitYear:
+		known |= knowCentury
+	case stdLongMonth, stdMonth, stdNumMonth, stdZeroMonth:
+		known |= knowMonth
+	case stdDay, stdUnderDay, stdZeroDay:
+		known |= knowDay
+	case stdYearDay:
+		known |= knowYearDay
+	case stdLongWeekDay, stdWeekDay, stdZeroBasedNumWeekDay, stdNumWeekDay:
+		known |= knowWeekDay
+	case stdWeekOfYear:
+		known |= knowWeekSun
+	case stdMonFirstWeekOfYear:
+		known |= knowWeekMon
+	case stdISO8601WeekYear, stdISO8601LongWeekYear:
+		known |= knowISOYear
+	case stdISO8601Week:
+		known |= knowISOWeek
+	case stdPM, stdpm:
+		known |= knowHalfDay
+	case stdHour12, stdZeroHour12, stdUnderHour12:
+		known |= knowHour12
+	case stdHour, stdUnderHour:
+		known |= knowHour
+	case stdMinute, stdZeroMinute:
+		known |= knowMinute
+	case stdSecond, stdZeroSecond:
+		known |= knowSecond
+	case stdUnix:
+		known |= knowUnix
+	case stdUnixMilli:
+		known |= knowUnix
+		if digits < 3 {
+			digits = 3
+		}
+	case stdFracSecond0, stdFracSecond9:
+		if n := std >> stdValueShift; n > digits {
+			digits = n
+		}
+	case stdTZ, stdNumTZ, stdISO8601TZ:
+		return known, digits, false
+	case stdComposite:
+		layout := compositeLayout(nil, std>>stdValueShift, true)
+		for layout != "" {
+			var ok bool
+			_, std, layout = nextStdChunk(layout)
+			if known, digits, ok = knownFields(known, digits, std); !ok {
+				return known, digits, false
+			}
+		}
+	}
+	return known, digits, true
+}
+
+// spanRank returns the rank of the span of time the known fields name.
+func spanRank(known, digits int) int {
+	has := func(fields int) bool { return known&fields == fields }
+
+	rank := rankNone
+	switch {
+	case has(knowUnix):
+		rank = rankSecond
+	case has(knowYear | knowMonth | knowDay), has(knowYear | knowYearDay),
+		has(knowYear | knowWeekSun | knowWeekDay), has(knowYear | knowWeekMon | knowWeekDay),
+		has(knowISOYear | knowISOWeek | knowWeekDay):
+		rank = rankDay
+		switch {
+		case has(knowHour | knowMinute | knowSecond), has(knowHour12 | knowHalfDay | knowMinute | knowSecond):
+			rank = rankSecond
+		case has(knowHour | knowMinute), has(knowHour12 | knowHalfDay | knowMinute):
+			rank = rankMinute
+		case has(knowHour), has(knowHour12 | knowHalfDay):
+			rank = rankHour
+		case has(knowHalfDay):
+			rank = rankHalfDay
+		}
+	case has(knowYear | knowWeekSun), has(knowYear | knowWeekMon), has(knowISOYear | knowISOWeek):
+		rank = rankWeek
+	case has(knowYear | knowMonth):
+		rank = rankMonth
+	case has(knowYear), has(knowISOYear):
+		rank = rankYear
+	case has(knowCentury):
+		rank = rankCentury
+	}
+	if rank == rankSecond {
+		if digits > 9 {
+			digits = 9
+		}
+		rank += digits
+	}
+	return rank
+}
+
+// fixedWidth reports whether the output of std, without a following
+// separator, cannot be the start of another of its outputs.
+func fixedWidth(std int) bool {
+	if std>>stdPadShift&3 == stdPadNone {
+		return false
+	}
+	switch std & stdMask {
+	case stdZeroMonth, stdMonth, stdWeekDay, stdZeroBasedNumWeekDay, stdNumWeekDay,
+		stdWeekOfYear, stdMonFirstWeekOfYear, stdUnderDay, stdZeroDay,
+		stdHour, stdZeroHour12, stdZeroMinute, stdZeroSecond, stdUnderHour, stdUnderHour12,
+		stdLongYear, stdYear, stdFirstTwoDigitYear, stdYearDay,
+		stdISO8601WeekYear, stdISO8601LongWeekYear, stdISO8601Week,
+		stdPM, stdpm, stdFracSecond0, stdComposite:
+		return true
+	}
+	return false
+}
+
+// isSeparator reports whether the literal text s following a specifier
+// ends its output.
+func isSeparator(s string) bool {
+	if s == "" {
+		return false
+	}
+	c := s[0]
+	return !('0' <= c && c <= '9' || 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || c == ' ')
+}
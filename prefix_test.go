@@ -0,0 +1,186 @@
+// Copyright 2018 Timon Wong. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package strftime_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/imperfectgo/go-strftime"
+)
+
+func TestPrefixCover(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip("time zone database unavailable:", err)
+	}
+
+	var febDays []string
+	for d := 1; d <= 29; d++ {
+		febDays = append(febDays, strftime.Format(time.Date(2024, 2, d, 0, 0, 0, 0, time.UTC), "%Y/%-m%d"))
+	}
+
+	tests := []struct {
+		layout   string
+		from, to time.Time
+		expected []string
+		wide     bool // the cover is not exact
+	}{
+		{
+			layout: "events/%Y/%m/%d/%H/",
+			from:   time.Date(2024, 1, 31, 22, 0, 0, 0, time.UTC),
+			to:     time.Date(2024, 3, 2, 1, 30, 0, 0, time.UTC),
+			expected: []string{
+				"events/2024/01/31/22/", "events/2024/01/31/23/",
+				"events/2024/02/",
+				"events/2024/03/01/",
+				"events/2024/03/02/00/", "events/2024/03/02/01/",
+			},
+		},
+		{
+			layout:   "events/%Y/%m/%d/%H/",
+			from:     time.Date(2023, 12, 1, 0, 0, 0, 0, time.UTC),
+			to:       time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+			expected: []string{"events/2023/12/", "events/2024/"},
+		},
+		{
+			// No separators: fixed-width fields still form prefixes.
+			layout:   "%Y%m%d%H",
+			from:     time.Date(2024, 2, 28, 23, 0, 0, 0, time.UTC),
+			to:       time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC),
+			expected: []string{"2024022823", "20240229", "202403"},
+		},
+		{
+			layout:   "%Y/%-m/%-d/",
+			from:     time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC),
+			to:       time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+			expected: []string{"2024/1/31/", "2024/2/"},
+		},
+		{
+			// "2024/1" would also be a prefix of "2024/10".
+			layout:   "%Y/%-m%d",
+			from:     time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC),
+			to:       time.Date(2024, 3, 3, 0, 0, 0, 0, time.UTC),
+			expected: append(append([]string{"2024/131"}, febDays...), "2024/301", "2024/302"),
+		},
+		{
+			// "2024/3/2/1" would also be a prefix of "2024/3/2/10".
+			layout:   "%Y/%-m/%-d/%-H",
+			from:     time.Date(2024, 3, 1, 22, 0, 0, 0, time.UTC),
+			to:       time.Date(2024, 3, 2, 3, 0, 0, 0, time.UTC),
+			expected: []string{"2024/3/1/", "2024/3/2/"},
+			wide:     true,
+		},
+		{
+			// No prefixes at all.
+			layout:   "%-H",
+			from:     time.Date(2024, 3, 1, 22, 0, 0, 0, time.UTC),
+			to:       time.Date(2024, 3, 2, 3, 0, 0, 0, time.UTC),
+			expected: []string{"22", "23", "0", "1", "2"},
+			wide:     true,
+		},
+		{
+			layout:   "%s",
+			from:     time.Unix(1700000000, 0),
+			to:       time.Unix(1700000003, 0),
+			expected: []string{"1700000000", "1700000001", "1700000002"},
+			wide:     true,
+		},
+		{
+			// Not hierarchical.
+			layout:   "%d-%m-%Y",
+			from:     time.Date(2024, 1, 30, 0, 0, 0, 0, time.UTC),
+			to:       time.Date(2024, 2, 2, 0, 0, 0, 0, time.UTC),
+			expected: []string{"30-01-2024", "31-01-2024", "01-02-2024"},
+		},
+		{
+			layout:   "%G/W%V/%u",
+			from:     time.Date(2024, 12, 28, 0, 0, 0, 0, time.UTC),
+			to:       time.Date(2025, 1, 13, 0, 0, 0, 0, time.UTC),
+			expected: []string{"2024/W52/6", "2024/W52/7", "2025/W01/", "2025/W02/"},
+		},
+		{
+			// The day clocks are set forward is whole in 23 hours.
+			layout:   "%Y/%m/%d/%H/",
+			from:     time.Date(2024, 3, 10, 0, 0, 0, 0, newYork),
+			to:       time.Date(2024, 3, 11, 1, 0, 0, 0, newYork),
+			expected: []string{"2024/03/10/", "2024/03/11/00/"},
+		},
+		{
+			layout: "%Y/%m/%d/%H/",
+			from:   time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+			to:     time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tt := range tests {
+		actual := strftime.PrefixCover(tt.layout, tt.from, tt.to)
+		if !reflect.DeepEqual(actual, tt.expected) {
+			t.Errorf("PrefixCover(%q, %v, %v): expected: %q; actual: %q", tt.layout, tt.from, tt.to, tt.expected, actual)
+			continue
+		}
+		checkCover(t, tt.layout, tt.from, tt.to, actual, !tt.wide)
+	}
+}
+
+// TestPrefixCoverFarFuture checks times past the end of the transition
+// table, where daylight saving time follows a rule instead.
+func TestPrefixCoverFarFuture(t *testing.T) {
+	london, err := time.LoadLocation("Europe/London")
+	if err != nil {
+		t.Skip("time zone database unavailable:", err)
+	}
+	from := time.Date(2040, 6, 1, 0, 0, 0, 0, london)
+	to := time.Date(2042, 6, 1, 0, 0, 0, 0, london)
+	expected := []string{
+		"2040/06/", "2040/07/", "2040/08/", "2040/09/", "2040/10/", "2040/11/", "2040/12/",
+		"2041/",
+		"2042/01/", "2042/02/", "2042/03/", "2042/04/", "2042/05/",
+	}
+
+	var actual []string
+	if !within(5*time.Second, func() { actual = strftime.PrefixCover("%Y/%m/", from, to) }) {
+		t.Fatalf("PrefixCover(%q, %v, %v) did not return", "%Y/%m/", from, to)
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("PrefixCover(%q, %v, %v): expected: %q; actual: %q", "%Y/%m/", from, to, expected, actual)
+	}
+}
+
+// checkCover checks that the outputs of layout around the range start
+// with exactly one of prefixes if they are in the range, and with none
+// otherwise. If not exact, it only checks that those in the range start
+// with one or more.
+func checkCover(t *testing.T, layout string, from, to time.Time, prefixes []string, exact bool) {
+	t.Helper()
+	inRange := make(map[string]bool)
+	for _, key := range strftime.ExpandAll(layout, from, to) {
+		inRange[key] = true
+	}
+	margin := 45 * 24 * time.Hour
+	if strftime.Granularity(layout) <= strftime.UnitMinute {
+		// Keep the outputs of fine layouts to a manageable number.
+		margin = time.Hour
+	}
+	for _, key := range strftime.ExpandAll(layout, from.Add(-margin), to.Add(margin)) {
+		matches := 0
+		for _, p := range prefixes {
+			if strings.HasPrefix(key, p) {
+				matches++
+			}
+		}
+		if !exact {
+			if inRange[key] && matches == 0 {
+				t.Errorf("%q: %q matches no prefix", layout, key)
+			}
+			continue
+		}
+		if expected := map[bool]int{true: 1}[inRange[key]]; matches != expected {
+			t.Errorf("%q: %q matches %d prefixes; expected %d", layout, key, matches, expected)
+		}
+	}
+}
@@ -0,0 +1,263 @@
+// Copyright 2018 Timon Wong. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package strftime
+
+import (
+	"regexp"
+	"sort"
+	"time"
+)
+
+// Regexp returns a regular expression matching the outputs of Format for
+// layout, for scraping logs or finding files named after a layout:
+//
+//	// (?P<Y>\d{4})-(?P<m>0[1-9]|1[0-2])-(?P<d>0[1-9]|[12]\d|3[01])\.log
+//	re, err := strftime.Regexp("%Y-%m-%d.log")
+//
+// Each specifier becomes a capture group named after its conversion
+// character, holding a pattern as tight as its output allows, and the
+// literal text is escaped. Composite specifiers are expanded, so "%F"
+// yields the groups Y, m and d. A name may occur more than once.
+//
+// The expression is not anchored; "^" + re.String() + "$" matches whole
+// outputs only. Unknown or dangling specifiers are reported as by Validate.
+func Regexp(layout string) (*regexp.Regexp, error) {
+	return RegexpLocale(layout, nil)
+}
+
+// RegexpLocale is like Regexp but matches the outputs of FormatLocale with
+// loc. A nil loc selects English.
+func RegexpLocale(layout string, loc *Locale) (*regexp.Regexp, error) {
+	if err := Validate(layout); err != nil {
+		return nil, err
+	}
+	return regexp.Compile(string(appendPattern(nil, layout, loc, false)))
+}
+
+// appendPattern appends the regular expression for layout to b. nested
+// reports whether layout is the expansion of a composite.
+func appendPattern(b []byte, layout string, loc *Locale, nested bool) []byte {
+	for layout != "" {
+		prefix, std, suffix := nextStdChunk(layout)
+		b = append(b, regexp.QuoteMeta(prefix)...)
+		if std == 0 {
+			break
+		}
+		stdstr := layout[len(prefix) : len(layout)-len(suffix)]
+		layout = suffix
+
+		switch std & stdMask {
+		case stdNop:
+		case stdUnknown:
+			// Only found in the expansion of a locale composite, which
+			// Format copies through.
+			b = append(b, regexp.QuoteMeta(stdstr)...)
+		case stdComposite:
+			b = appendPattern(b, compositeLayout(loc, std>>stdValueShift, nested), loc, true)
+		default:
+			b = append(b, "(?P<"...)
+			b = append(b, stdstr[len(stdstr)-1])
+			b = append(b, '>')
+			b = append(b, stdPattern(std, loc)...)
+			b = append(b, ')')
+		}
+	}
+	return b
+}
+
+// stdPattern returns the regular expression for the outputs of std.
+func stdPattern(std int, loc *Locale) string {
+	switch std & stdMask {
+	case stdMonth, stdLongMonth, stdWeekDay, stdLongWeekDay, stdPM, stdpm:
+		return textPattern(std, namesPattern(stdNames(std, loc)))
+	case stdTZ:
+		return textPattern(std, `[A-Za-z]+|[+-]\d{2}(?:\d{2})?|`)
+	case stdNumTZ, stdISO8601TZ:
+		p := [...]string{
+			`[+-]\d{4}`,
+			`[+-]\d{2}:\d{2}`,
+			`[+-]\d{2}:\d{2}:\d{2}`,
+			`[+-]\d{2}(?::\d{2}){0,2}`,
+		}[std>>stdValueShift]
+		if std&stdMask == stdISO8601TZ {
+			p = "Z|" + p
+		}
+		return p
+	case stdFracSecond0, stdFracSecond9:
+		n := std >> stdValueShift
+		if n > 9 {
+			n = 9
+		}
+		digits := string(appendInt(nil, n, 0))
+		if std&stdMask == stdFracSecond9 {
+			return `\d{0,` + digits + `}`
+		}
+		return `\d{` + digits + `}`
+	case stdUnix, stdUnixMilli:
+		if std>>stdPadShift&3 == stdPadSpace || std>>stdPadShift&3 == stdPadNone && std&stdWidth != 0 {
+			return ` *-?\d+`
+		}
+		return `-?\d+`
+	}
+	return numberPattern(std)
+}
+
+// stdNames returns the names the textual std value may be formatted as.
+func stdNames(std int, loc *Locale) []string {
+	var names []string
+	switch std & stdMask {
+	case stdMonth, stdLongMonth:
+		for m := time.January; m <= time.December; m++ {
+			switch {
+			case loc == nil && std&stdMask == stdMonth:
+				names = append(names, m.String()[:3])
+			case loc == nil:
+				names = append(names, m.String())
+			case std&stdMask == stdMonth:
+				names = append(names, loc.ShortMonthNames[m-1])
+			default:
+				names = append(names, loc.LongMonthNames[m-1])
+			}
+		}
+	case stdWeekDay, stdLongWeekDay:
+		for d := time.Sunday; d <= time.Saturday; d++ {
+			switch {
+			case loc == nil && std&stdMask == stdWeekDay:
+				names = append(names, d.String()[:3])
+			case loc == nil:
+				names = append(names, d.String())
+			case std&stdMask == stdWeekDay:
+				names = append(names, loc.ShortDayNames[d])
+			default:
+				names = append(names, loc.LongDayNames[d])
+			}
+		}
+	case stdPM:
+		names = []string{"AM", "PM"}
+		if loc != nil {
+			names = []string{loc.AM, loc.PM}
+		}
+	case stdpm:
+		names = []string{"am", "pm"}
+		if loc != nil {
+			names = []string{string(appendLower(nil, loc.AM)), string(appendLower(nil, loc.PM))}
+		}
+	}
+	return names
+}
+
+// namesPattern returns an alternation of names, trying the longest first
+// so that a name is not cut short by another it starts with.
+func namesPattern(names []string) string {
+	sorted := make([]string, 0, len(names))
+	seen := make(map[string]bool, len(names))
+	for _, name := range names {
+		if !seen[name] {
+			seen[name] = true
+			sorted = append(sorted, name)
+		}
+	}
+	sort.SliceStable(sorted, func(i, j int) bool { return len(sorted[i]) > len(sorted[j]) })
+
+	var b []byte
+	for i, name := range sorted {
+		if i > 0 {
+			b = append(b, '|')
+		}
+		b = append(b, regexp.QuoteMeta(name)...)
+	}
+	return string(b)
+}
+
+// textPattern applies the case flag and field width of the textual std
+// value to the alternation p.
+func textPattern(std int, p string) string {
+	cas := std >> stdCaseShift & 3
+	if std&stdWidth == 0 {
+		if cas != 0 {
+			return "(?i:" + p + ")"
+		}
+		return p
+	}
+	if cas != 0 {
+		p = "(?i:" + p + ")"
+	} else {
+		p = "(?:" + p + ")"
+	}
+	if std>>stdPadShift&3 == stdPadZero {
+		return "0*" + p
+	}
+	return " *" + p
+}
+
+// numberPatterns holds the natural width of a numeric std value and the
+// regular expressions for its outputs padded with zeros, with spaces and
+// not padded. Alternatives of the latter try longer numbers first.
+type numberPatterns struct {
+	width             int
+	zero, space, none string
+}
+
+// numberPattern returns the regular expression for the outputs of the
+// numeric std value, as padded by appendFlagged.
+func numberPattern(std int) string {
+	var p numberPatterns
+	switch std & stdMask {
+	case stdZeroMonth, stdNumMonth, stdZeroHour12, stdHour12, stdUnderHour12:
+		p = numberPatterns{2, `0[1-9]|1[0-2]`, ` [1-9]|1[0-2]`, `1[0-2]|[1-9]`}
+	case stdZeroDay, stdDay, stdUnderDay:
+		p = numberPatterns{2, `0[1-9]|[12]\d|3[01]`, ` [1-9]|[12]\d|3[01]`, `[12]\d|3[01]|[1-9]`}
+	case stdHour, stdUnderHour:
+		p = numberPatterns{2, `[01]\d|2[0-3]`, ` \d|1\d|2[0-3]`, `2[0-3]|1?\d`}
+	case stdZeroMinute, stdMinute:
+		p = numberPatterns{2, `[0-5]\d`, ` \d|[1-5]\d`, `[1-5]?\d`}
+	case stdZeroSecond, stdSecond:
+		p = numberPatterns{2, `[0-5]\d|60`, ` \d|[1-5]\d|60`, `60|[1-5]?\d`}
+	case stdYearDay:
+		p = numberPatterns{3,
+			`00[1-9]|0[1-9]\d|[12]\d\d|3[0-5]\d|36[0-6]`,
+			`  [1-9]| [1-9]\d|[12]\d\d|3[0-5]\d|36[0-6]`,
+			`[12]\d\d|3[0-5]\d|36[0-6]|[1-9]\d?`}
+	case stdWeekOfYear, stdMonFirstWeekOfYear:
+		p = numberPatterns{2, `[0-4]\d|5[0-3]`, ` \d|[1-4]\d|5[0-3]`, `5[0-3]|[1-4]?\d`}
+	case stdISO8601Week:
+		p = numberPatterns{2, `0[1-9]|[1-4]\d|5[0-3]`, ` [1-9]|[1-4]\d|5[0-3]`, `[1-4]\d|5[0-3]|[1-9]`}
+	case stdZeroBasedNumWeekDay:
+		p = numberPatterns{1, `[0-6]`, `[0-6]`, `[0-6]`}
+	case stdNumWeekDay:
+		p = numberPatterns{1, `[1-7]`, `[1-7]`, `[1-7]`}
+	case stdLongYear, stdISO8601LongWeekYear:
+		p = numberPatterns{4, `\d{4}`, `[ \d]{3}\d`, `\d{1,4}`}
+	default: // stdYear, stdFirstTwoDigitYear, stdISO8601WeekYear
+		p = numberPatterns{2, `\d{2}`, `[ \d]\d`, `\d{1,2}`}
+	}
+
+	switch std & stdMask {
+	case stdNumMonth, stdDay, stdHour12, stdMinute, stdSecond:
+		return p.none
+	}
+	width := 0
+	if std&stdWidth != 0 {
+		width = std >> stdValueShift
+	}
+	pad := std >> stdPadShift & 3
+	switch {
+	case pad == 0:
+		pad = stdDefaultPad(std)
+	case pad == stdPadNone && width > 0:
+		pad = stdPadSpace
+	case pad == stdPadNone:
+		return p.none
+	}
+
+	s, c := p.zero, "0"
+	if pad == stdPadSpace {
+		s, c = p.space, " "
+	}
+	if width <= p.width {
+		return s
+	}
+	return c + "{" + string(appendInt(nil, width-p.width, 0)) + "}(?:" + s + ")"
+}
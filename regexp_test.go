@@ -0,0 +1,164 @@
+// Copyright 2018 Timon Wong. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package strftime_test
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/imperfectgo/go-strftime"
+)
+
+func TestRegexp(t *testing.T) {
+	tests := []struct {
+		layout   string
+		expected string
+	}{
+		{layout: "%Y", expected: `(?P<Y>\d{4})`},
+		{layout: "%z", expected: `(?P<z>[+-]\d{4})`},
+		{layout: "%:z", expected: `(?P<z>[+-]\d{2}:\d{2})`},
+		{layout: "%b", expected: `(?P<b>Jan|Feb|Mar|Apr|May|Jun|Jul|Aug|Sep|Oct|Nov|Dec)`},
+		{layout: "%p", expected: `(?P<p>AM|PM)`},
+		{layout: "%^a", expected: `(?P<a>(?i:Sun|Mon|Tue|Wed|Thu|Fri|Sat))`},
+		{layout: "%F", expected: `(?P<Y>\d{4})-(?P<m>0[1-9]|1[0-2])-(?P<d>0[1-9]|[12]\d|3[01])`},
+		{layout: "%e", expected: `(?P<e> [1-9]|[12]\d|3[01])`},
+		{layout: "%-d", expected: `(?P<d>[12]\d|3[01]|[1-9])`},
+		{layout: "%4d", expected: `(?P<d>0{2}(?:0[1-9]|[12]\d|3[01]))`},
+		{layout: "%3N", expected: `(?P<N>\d{3})`},
+		{layout: "app.%Y[%%]", expected: `app\.(?P<Y>\d{4})\[%\]`},
+		{layout: "%n%t", expected: "\n\t"},
+	}
+
+	for _, tt := range tests {
+		re, err := strftime.Regexp(tt.layout)
+		if err != nil {
+			t.Errorf("Regexp(%q): unexpected error: %v", tt.layout, err)
+			continue
+		}
+		if actual := re.String(); actual != tt.expected {
+			t.Errorf("Regexp(%q): expected: %q; actual: %q", tt.layout, tt.expected, actual)
+		}
+	}
+}
+
+func TestRegexpError(t *testing.T) {
+	for _, layout := range []string{"%!", "%Y-%"} {
+		if _, err := strftime.Regexp(layout); err == nil {
+			t.Errorf("Regexp(%q): expected an error", layout)
+		} else if _, ok := err.(*strftime.LayoutError); !ok {
+			t.Errorf("Regexp(%q): expected a *LayoutError; actual: %T", layout, err)
+		}
+	}
+}
+
+// TestRegexpMatchesFormat checks that the expression for a layout matches
+// the whole of its outputs, across flags, widths, zones and locales.
+func TestRegexpMatchesFormat(t *testing.T) {
+	layouts := []string{
+		"%a %A %b %B %c %C %d %D %e %F %g %G %H %I %j %k %l %m %M %p %P %r %R %s %S %T %u %U %V %w %W %x %X %y %Y %z %Z %%",
+		"%-d/%-m/%-y %-H:%-M:%-S %-I %-j %-U %-V",
+		"%_d %_m %_H %_M %_j %_Y %_y %0e %0k %0l",
+		"%5d %_5H %-4m %6Y %10B %^b %#Z %^P %8p",
+		"%:z %::z %:::z %#z %#:z",
+		"%f %N %3N %Q %_15s",
+		"[%Y] (%m) {%d} a.b*c+d?e|f\\g^h$",
+	}
+	locales := []*strftime.Locale{nil, strftime.LocaleEnUS, strftime.LocaleDeDE, strftime.LocaleJaJP, strftime.LocalePtBR}
+	kolkata := time.FixedZone("IST", 5*3600+30*60)
+	unnamed := time.FixedZone("", -3*3600)
+	times := []time.Time{
+		time.Date(2018, time.July, 9, 13, 14, 15, 123456789, time.UTC),
+		time.Date(1950, time.December, 31, 0, 5, 59, 0, kolkata),
+		time.Date(2016, time.January, 1, 12, 0, 0, 0, unnamed),
+		time.Date(2021, time.January, 3, 23, 59, 60, 1000, time.UTC),
+		time.Date(2020, time.December, 31, 9, 9, 9, 999000000, time.UTC),
+		time.Date(1969, time.December, 31, 23, 59, 59, 0, time.UTC),
+	}
+
+	for _, loc := range locales {
+		for _, layout := range layouts {
+			re, err := strftime.RegexpLocale(layout, loc)
+			if err != nil {
+				t.Fatalf("RegexpLocale(%q): unexpected error: %v", layout, err)
+			}
+			anchored := regexp.MustCompile("^(?:" + re.String() + ")$")
+			for _, tm := range times {
+				s := strftime.FormatLocale(tm, layout, loc)
+				if !anchored.MatchString(s) {
+					t.Errorf("RegexpLocale(%q) does not match %q", layout, s)
+				}
+			}
+		}
+	}
+}
+
+func TestRegexpSubexp(t *testing.T) {
+	re, err := strftime.Regexp("access-%Y%m%d-%H.log")
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := re.FindStringSubmatch("/var/log/access-20240310-07.log.gz")
+	if m == nil {
+		t.Fatal("expected a match")
+	}
+	for name, expected := range map[string]string{"Y": "2024", "m": "03", "d": "10", "H": "07"} {
+		if actual := group(re, m, name); actual != expected {
+			t.Errorf("group %s: expected: %q; actual: %q", name, expected, actual)
+		}
+	}
+
+	for _, s := range []string{"access-20241310-07.log", "access-20240332-07.log", "access-20240310-24.log", "access-2024031-07.log"} {
+		if re.MatchString(s) {
+			t.Errorf("expected no match for %q", s)
+		}
+	}
+}
+
+func TestRegexpLocale(t *testing.T) {
+	tests := []struct {
+		layout string
+		loc    *strftime.Locale
+		value  string
+		group  string
+		match  string
+	}{
+		{layout: "%d. %B %Y", loc: strftime.LocaleDeDE, value: "09. März 2018", group: "B", match: "März"},
+		{layout: "%A", loc: strftime.LocaleJaJP, value: "月曜日", group: "A", match: "月曜日"},
+		{layout: "%r", loc: strftime.LocaleJaJP, value: "午後01時14分15秒", group: "p", match: "午後"},
+		{layout: "%x", loc: strftime.LocaleDeDE, value: "09.07.2018", group: "d", match: "09"},
+		{layout: "%b", loc: strftime.LocalePtBR, value: "set", group: "b", match: "set"},
+		// Unpadded numbers try the longest first.
+		{layout: "%-H", value: "23", group: "H", match: "23"},
+		{layout: "%-j", value: "366", group: "j", match: "366"},
+	}
+
+	for _, tt := range tests {
+		re, err := strftime.RegexpLocale(tt.layout, tt.loc)
+		if err != nil {
+			t.Errorf("RegexpLocale(%q): unexpected error: %v", tt.layout, err)
+			continue
+		}
+		m := re.FindStringSubmatch(tt.value)
+		if m == nil {
+			t.Errorf("RegexpLocale(%q): expected a match for %q", tt.layout, tt.value)
+			continue
+		}
+		if actual := group(re, m, tt.group); actual != tt.match {
+			t.Errorf("RegexpLocale(%q) on %q, group %s: expected: %q; actual: %q", tt.layout, tt.value, tt.group, tt.match, actual)
+		}
+	}
+}
+
+// group returns the submatch of the first group of re named name, as
+// Regexp.SubexpIndex does from Go 1.15 on.
+func group(re *regexp.Regexp, m []string, name string) string {
+	for i, n := range re.SubexpNames() {
+		if n == name {
+			return m[i]
+		}
+	}
+	return ""
+}
@@ -0,0 +1,175 @@
+// Copyright 2018 Timon Wong. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package rotate provides a file writer that rotates by time, with file
+// names built from a strftime pattern.
+package rotate
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	strftime "github.com/imperfectgo/go-strftime"
+)
+
+// Options configure a Writer. The zero value is ready to use.
+type Options struct {
+	// Clock returns the current time, which the file name is formatted
+	// from. It defaults to time.Now; return times in another location,
+	// such as UTC, to name files after that location's clock.
+	Clock func() time.Time
+
+	// Symlink, if not empty, is the path of a symbolic link kept pointing
+	// at the current file.
+	Symlink string
+
+	// FileMode is the permission of new files, 0644 if zero. Missing
+	// directories are created with permission 0755.
+	FileMode os.FileMode
+}
+
+// Writer is an io.WriteCloser that writes to the file named by formatting
+// its pattern with the current time, such as "access-%Y%m%d-%H.log". It
+// switches to a new file on the first write after the name changes, and
+// appends to files that exist already.
+//
+// A Writer may be used by multiple goroutines at once.
+type Writer struct {
+	layout  *strftime.Layout
+	clock   func() time.Time
+	symlink string
+	mode    os.FileMode
+
+	mu     sync.Mutex
+	file   *os.File
+	name   string
+	buf    []byte
+	closed bool
+}
+
+// New returns a Writer for the file name pattern. It fails if pattern is not
+// a valid layout, as reported by strftime.Validate. opts may be nil. No file
+// is opened before the first write.
+func New(pattern string, opts *Options) (*Writer, error) {
+	if err := strftime.Validate(pattern); err != nil {
+		return nil, err
+	}
+	w := &Writer{
+		layout: strftime.MustCompile(pattern),
+		clock:  time.Now,
+		mode:   0644,
+	}
+	if opts != nil {
+		if opts.Clock != nil {
+			w.clock = opts.Clock
+		}
+		if opts.FileMode != 0 {
+			w.mode = opts.FileMode
+		}
+		w.symlink = opts.Symlink
+	}
+	return w, nil
+}
+
+// Write writes p to the current file, switching files first if the name
+// has changed. If the symbolic link cannot be updated, p is written all the
+// same and the error returned.
+func (w *Writer) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed {
+		return 0, os.ErrClosed
+	}
+
+	var linkErr error
+	w.buf = w.layout.AppendFormat(w.buf[:0], w.clock())
+	if w.file == nil || string(w.buf) != w.name {
+		if err := w.open(string(w.buf)); err != nil {
+			return 0, err
+		}
+		linkErr = w.link()
+	}
+
+	n, err := w.file.Write(p)
+	if err == nil {
+		err = linkErr
+	}
+	return n, err
+}
+
+// Name returns the name of the current file, or "" before the first write.
+func (w *Writer) Name() string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.name
+}
+
+// Close closes the current file. Writes after Close fail with os.ErrClosed.
+func (w *Writer) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed {
+		return os.ErrClosed
+	}
+	w.closed = true
+	if w.file == nil {
+		return nil
+	}
+	err := w.file.Close()
+	w.file = nil
+	return err
+}
+
+// open switches to the file name, creating it and its directory as needed.
+// The current file is kept if name cannot be opened.
+func (w *Writer) open(name string) error {
+	if dir := filepath.Dir(name); dir != "." {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return err
+		}
+	}
+	f, err := os.OpenFile(name, os.O_WRONLY|os.O_APPEND|os.O_CREATE, w.mode)
+	if err != nil {
+		return err
+	}
+	if w.file != nil {
+		w.file.Close()
+	}
+	w.file = f
+	w.name = name
+	return nil
+}
+
+// link points the symbolic link at the current file. The link is replaced
+// by renaming a new one over it, so that it always exists once created. Its
+// target is relative to the link's directory where possible.
+func (w *Writer) link() error {
+	if w.symlink == "" {
+		return nil
+	}
+	target, err := filepath.Abs(w.name)
+	if err != nil {
+		return err
+	}
+	if dir, err := filepath.Abs(filepath.Dir(w.symlink)); err == nil {
+		if rel, err := filepath.Rel(dir, target); err == nil {
+			target = rel
+		}
+	}
+
+	tmp := w.symlink + ".tmp"
+	os.Remove(tmp)
+	if err := os.Symlink(target, tmp); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, w.symlink); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return nil
+}
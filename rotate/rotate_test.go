@@ -0,0 +1,178 @@
+// Copyright 2018 Timon Wong. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rotate_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/imperfectgo/go-strftime/rotate"
+)
+
+// fakeClock is a clock that only moves when told to.
+type fakeClock struct{ t time.Time }
+
+func (c *fakeClock) Now() time.Time { return c.t }
+
+func (c *fakeClock) Add(d time.Duration) { c.t = c.t.Add(d) }
+
+func tempDir(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "rotate")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func readFile(t *testing.T, name string) string {
+	b, err := ioutil.ReadFile(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
+
+func write(t *testing.T, w *rotate.Writer, s string) {
+	if _, err := w.Write([]byte(s)); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestWriter(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+
+	clock := &fakeClock{t: time.Date(2008, 9, 3, 20, 59, 0, 0, time.UTC)}
+	w, err := rotate.New(filepath.Join(dir, "%Y", "access-%Y%m%d-%H.log"), &rotate.Options{Clock: clock.Now})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if name := w.Name(); name != "" {
+		t.Errorf("expected no file before the first write; actual: %q", name)
+	}
+
+	write(t, w, "a\n")
+	clock.Add(30 * time.Second)
+	write(t, w, "b\n")
+	clock.Add(30 * time.Second)
+	write(t, w, "c\n")
+
+	first := filepath.Join(dir, "2008", "access-20080903-20.log")
+	second := filepath.Join(dir, "2008", "access-20080903-21.log")
+	if name := w.Name(); name != second {
+		t.Errorf("expected: %q; actual: %q", second, name)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if actual := readFile(t, first); actual != "a\nb\n" {
+		t.Errorf("%s: expected: %q; actual: %q", first, "a\nb\n", actual)
+	}
+	if actual := readFile(t, second); actual != "c\n" {
+		t.Errorf("%s: expected: %q; actual: %q", second, "c\n", actual)
+	}
+
+	if _, err := w.Write([]byte("d\n")); err != os.ErrClosed {
+		t.Errorf("Write after Close: expected os.ErrClosed; actual: %v", err)
+	}
+	if err := w.Close(); err != os.ErrClosed {
+		t.Errorf("Close after Close: expected os.ErrClosed; actual: %v", err)
+	}
+}
+
+func TestWriterAppends(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+
+	clock := &fakeClock{t: time.Date(2008, 9, 3, 20, 4, 26, 0, time.UTC)}
+	pattern := filepath.Join(dir, "%F.log")
+	for _, s := range []string{"a\n", "b\n"} {
+		w, err := rotate.New(pattern, &rotate.Options{Clock: clock.Now})
+		if err != nil {
+			t.Fatal(err)
+		}
+		write(t, w, s)
+		w.Close()
+	}
+
+	if actual := readFile(t, filepath.Join(dir, "2008-09-03.log")); actual != "a\nb\n" {
+		t.Errorf("expected: %q; actual: %q", "a\nb\n", actual)
+	}
+}
+
+func TestWriterSymlink(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("symbolic links need extra privileges on windows")
+	}
+
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+
+	clock := &fakeClock{t: time.Date(2008, 9, 3, 23, 0, 0, 0, time.UTC)}
+	link := filepath.Join(dir, "current.log")
+	w, err := rotate.New(filepath.Join(dir, "logs", "%Y%m%d.log"), &rotate.Options{Clock: clock.Now, Symlink: link})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+
+	tests := []struct {
+		target string
+		text   string
+	}{
+		{target: filepath.Join("logs", "20080903.log"), text: "a\n"},
+		{target: filepath.Join("logs", "20080904.log"), text: "b\n"},
+	}
+
+	for _, tt := range tests {
+		write(t, w, tt.text)
+		target, err := os.Readlink(link)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if target != tt.target {
+			t.Errorf("expected link to %q; actual: %q", tt.target, target)
+		}
+		if actual := readFile(t, link); actual != tt.text {
+			t.Errorf("expected: %q; actual: %q", tt.text, actual)
+		}
+		clock.Add(time.Hour)
+	}
+}
+
+func TestNewInvalidPattern(t *testing.T) {
+	if _, err := rotate.New("app-%!.log", nil); err == nil {
+		t.Error("expected an error for an unknown specifier")
+	}
+}
+
+func TestWriterAllocs(t *testing.T) {
+	if runtime.GOOS == "js" || runtime.GOOS == "wasip1" {
+		t.Skip("writing to a file allocates on " + runtime.GOOS)
+	}
+
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+
+	clock := &fakeClock{t: time.Date(2008, 9, 3, 20, 4, 26, 0, time.UTC)}
+	w, err := rotate.New(filepath.Join(dir, "%Y%m%d-%H.log"), &rotate.Options{Clock: clock.Now})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+
+	p := []byte("a\n")
+	write(t, w, "")
+	allocs := testing.AllocsPerRun(100, func() {
+		w.Write(p)
+	})
+	if allocs != 0 {
+		t.Errorf("expected no allocations without rotation; actual: %v", allocs)
+	}
+}
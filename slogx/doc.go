@@ -0,0 +1,18 @@
+// Copyright 2018 Timon Wong. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package slogx renders the time values of log/slog records with a
+// strftime layout. It requires Go 1.21 or later and is empty otherwise.
+//
+// ReplaceAttr plugs into slog.HandlerOptions and covers every time value a
+// built-in handler writes, including the record time under slog.TimeKey:
+//
+//	l := strftime.MustCompile("%Y-%m-%d %H:%M:%S.%3N")
+//	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{
+//		ReplaceAttr: slogx.ReplaceAttr(l, nil),
+//	}))
+//
+// Handler wraps any other slog.Handler and rewrites the time values among
+// its attributes before they reach it.
+package slogx
@@ -0,0 +1,132 @@
+// Copyright 2018 Timon Wong. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build go1.21
+// +build go1.21
+
+package slogx
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	strftime "github.com/imperfectgo/go-strftime"
+)
+
+// ReplaceAttr returns a func for slog.HandlerOptions.ReplaceAttr that
+// formats time values with l. If next is not nil, it is applied first and
+// its result formatted.
+func ReplaceAttr(l *strftime.Layout, next func(groups []string, a slog.Attr) slog.Attr) func(groups []string, a slog.Attr) slog.Attr {
+	return func(groups []string, a slog.Attr) slog.Attr {
+		if next != nil {
+			a = next(groups, a)
+		}
+		if a.Value.Kind() == slog.KindTime {
+			a.Value = slog.StringValue(format(l, a.Value.Time()))
+		}
+		return a
+	}
+}
+
+// Handler is a slog.Handler that formats the time values among the
+// attributes of records, including those added with WithAttrs and those
+// nested in groups, before passing them on to another handler.
+//
+// The record time itself is left to the wrapped handler; use ReplaceAttr
+// in its options to format it as well.
+type Handler struct {
+	h slog.Handler
+	l *strftime.Layout
+}
+
+// NewHandler returns a Handler that formats time values with l and passes
+// records on to h.
+func NewHandler(h slog.Handler, l *strftime.Layout) *Handler {
+	return &Handler{h: h, l: l}
+}
+
+// Enabled reports whether the wrapped handler handles records at level.
+func (h *Handler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.h.Enabled(ctx, level)
+}
+
+// Handle formats the time values of r and passes it on. Records without
+// time values are passed on as they are.
+func (h *Handler) Handle(ctx context.Context, r slog.Record) error {
+	found := false
+	r.Attrs(func(a slog.Attr) bool {
+		found = hasTime(a)
+		return !found
+	})
+	if !found {
+		return h.h.Handle(ctx, r)
+	}
+
+	nr := slog.NewRecord(r.Time, r.Level, r.Message, r.PC)
+	r.Attrs(func(a slog.Attr) bool {
+		nr.AddAttrs(h.replace(a))
+		return true
+	})
+	return h.h.Handle(ctx, nr)
+}
+
+// WithAttrs returns a Handler whose wrapped handler has the attributes
+// attrs, with their time values formatted.
+func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	replaced := make([]slog.Attr, len(attrs))
+	for i, a := range attrs {
+		replaced[i] = h.replace(a)
+	}
+	return &Handler{h: h.h.WithAttrs(replaced), l: h.l}
+}
+
+// WithGroup returns a Handler whose wrapped handler starts the group name.
+func (h *Handler) WithGroup(name string) slog.Handler {
+	return &Handler{h: h.h.WithGroup(name), l: h.l}
+}
+
+// replace returns a with its time values formatted. Groups are copied only
+// when they hold a time value.
+func (h *Handler) replace(a slog.Attr) slog.Attr {
+	a.Value = a.Value.Resolve()
+	switch a.Value.Kind() {
+	case slog.KindTime:
+		a.Value = slog.StringValue(format(h.l, a.Value.Time()))
+	case slog.KindGroup:
+		if !hasTime(a) {
+			break
+		}
+		group := a.Value.Group()
+		replaced := make([]slog.Attr, len(group))
+		for i, ga := range group {
+			replaced[i] = h.replace(ga)
+		}
+		a.Value = slog.GroupValue(replaced...)
+	}
+	return a
+}
+
+// hasTime reports whether a is or holds a time value.
+func hasTime(a slog.Attr) bool {
+	v := a.Value.Resolve()
+	switch v.Kind() {
+	case slog.KindTime:
+		return true
+	case slog.KindGroup:
+		for _, ga := range v.Group() {
+			if hasTime(ga) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// format formats t with l through a stack buffer, so that the resulting
+// string is the only allocation.
+func format(l *strftime.Layout, t time.Time) string {
+	var buf [64]byte
+	return string(l.AppendFormat(buf[:0], t))
+}
@@ -0,0 +1,110 @@
+// Copyright 2018 Timon Wong. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build go1.21
+// +build go1.21
+
+package slogx_test
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/imperfectgo/go-strftime"
+	"github.com/imperfectgo/go-strftime/slogx"
+)
+
+var (
+	t1     = time.Date(2008, 9, 3, 20, 4, 26, 654321000, time.UTC)
+	layout = strftime.MustCompile("%Y/%m/%d %H:%M:%S.%3N")
+)
+
+// timeValuer resolves to t1.
+type timeValuer struct{}
+
+func (timeValuer) LogValue() slog.Value { return slog.TimeValue(t1) }
+
+func TestReplaceAttr(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{
+		ReplaceAttr: slogx.ReplaceAttr(layout, func(groups []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.LevelKey {
+				return slog.Attr{}
+			}
+			return a
+		}),
+	}))
+	logger.Handler().Handle(context.Background(), newRecord("hello",
+		slog.Time("at", t1),
+		slog.Group("g", slog.Time("at", t1), slog.Int("n", 1)),
+	))
+
+	expected := `time="2008/09/03 20:04:26.654" msg=hello at="2008/09/03 20:04:26.654" g.at="2008/09/03 20:04:26.654" g.n=1` + "\n"
+	if actual := buf.String(); actual != expected {
+		t.Errorf("expected: %q; actual: %q", expected, actual)
+	}
+}
+
+func TestHandler(t *testing.T) {
+	var buf bytes.Buffer
+	var h slog.Handler = slogx.NewHandler(slog.NewTextHandler(&buf, &slog.HandlerOptions{
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if len(groups) == 0 && (a.Key == slog.TimeKey || a.Key == slog.LevelKey) {
+				return slog.Attr{}
+			}
+			return a
+		},
+	}), layout)
+	h = h.WithAttrs([]slog.Attr{slog.Time("since", t1)}).WithGroup("g")
+
+	tests := []struct {
+		attrs    []slog.Attr
+		expected string
+	}{
+		{
+			attrs:    []slog.Attr{slog.Int("n", 1)},
+			expected: `msg=m since="2008/09/03 20:04:26.654" g.n=1`,
+		},
+		{
+			attrs:    []slog.Attr{slog.Time("at", t1), slog.Any("v", timeValuer{})},
+			expected: `msg=m since="2008/09/03 20:04:26.654" g.at="2008/09/03 20:04:26.654" g.v="2008/09/03 20:04:26.654"`,
+		},
+		{
+			attrs:    []slog.Attr{slog.Group("h", slog.String("s", "x"), slog.Time("at", t1))},
+			expected: `msg=m since="2008/09/03 20:04:26.654" g.h.s=x g.h.at="2008/09/03 20:04:26.654"`,
+		},
+	}
+
+	for _, tt := range tests {
+		buf.Reset()
+		if err := h.Handle(context.Background(), newRecord("m", tt.attrs...)); err != nil {
+			t.Fatal(err)
+		}
+		if actual := strings.TrimSuffix(buf.String(), "\n"); actual != tt.expected {
+			t.Errorf("expected: %q; actual: %q", tt.expected, actual)
+		}
+	}
+}
+
+func TestReplaceAttrAllocs(t *testing.T) {
+	replace := slogx.ReplaceAttr(layout, nil)
+	a := slog.Time("at", t1)
+	allocs := testing.AllocsPerRun(100, func() {
+		replace(nil, a)
+	})
+	// The formatted string itself.
+	if allocs != 1 {
+		t.Errorf("expected 1 allocation; actual: %v", allocs)
+	}
+}
+
+func newRecord(msg string, attrs ...slog.Attr) slog.Record {
+	r := slog.NewRecord(t1, slog.LevelInfo, msg, 0)
+	r.AddAttrs(attrs...)
+	return r
+}
@@ -0,0 +1,79 @@
+// Copyright 2018 Timon Wong. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package strftime
+
+import "time"
+
+// Span returns the half-open interval [start, end) of the times in loc that
+// layout formats as value: for example July 2018 for "%Y-%m" and "2018-07",
+// or the ISO 8601 week for "%G-W%V" and "2018-W28". value is read with
+// ParseInLocation, and the interval is that of Floor and NextChange.
+//
+// The specifiers of layout must name a single span of time, as the prefixes
+// of PrefixCover do: "%Y-%m-%d %H" does, while "%m-%d", which recurs every
+// year, does not. Unless the layout holds a zone, a value on the wall clock
+// skipped by a daylight saving time transition in loc is an error, and one
+// repeated spans both occurrences.
+func Span(layout, value string, loc *time.Location) (start, end time.Time, err error) {
+	g := layoutGranularity(layout)
+	if !singleSpan(layout, &g) {
+		return time.Time{}, time.Time{}, &ParseError{Layout: layout, Value: value, Message: ": layout does not name a single span of time"}
+	}
+	t, err := ParseInLocation(layout, value, loc)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	if !g.zone {
+		// Reading the value in loc moves a time in a gap of the wall clock
+		// out of it, which the wall clock itself, read in UTC, reveals.
+		wall, _ := Parse(layout, value)
+		if !sameOutput(t, wall, layout) {
+			return time.Time{}, time.Time{}, &ParseError{Layout: layout, Value: value, Message: ": time does not exist in " + loc.String()}
+		}
+	}
+	return g.floor(t, layout), g.next(t, layout), nil
+}
+
+// singleSpan reports whether the specifiers of layout name a single span of
+// time, given its granularity g: whether the finest of them refine a chain
+// from the year, or from the Unix epoch, down.
+func singleSpan(layout string, g *granularity) bool {
+	var known, digits int
+	for layout != "" {
+		var std int
+		_, std, layout = nextStdChunk(layout)
+		switch std & stdMask {
+		case 0, stdTZ, stdNumTZ, stdISO8601TZ:
+			continue
+		}
+		known, digits, _ = knownFields(known, digits, std)
+	}
+	rank := spanRank(known, digits)
+	if rank == rankNone {
+		return false
+	}
+	var unit Unit
+	switch {
+	case rank > rankSecond+6:
+		unit = UnitNanosecond
+	case rank > rankSecond+3:
+		unit = UnitMicrosecond
+	case rank > rankSecond:
+		unit = UnitMillisecond
+	default:
+		unit = [...]Unit{
+			rankCentury: UnitCentury,
+			rankYear:    UnitYear,
+			rankMonth:   UnitMonth,
+			rankWeek:    UnitWeek,
+			rankDay:     UnitDay,
+			rankHalfDay: UnitHalfDay,
+			rankHour:    UnitHour,
+			rankMinute:  UnitMinute,
+			rankSecond:  UnitSecond,
+		}[rank]
+	}
+	return unit == g.unit()
+}
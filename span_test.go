@@ -0,0 +1,102 @@
+// Copyright 2018 Timon Wong. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package strftime_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/imperfectgo/go-strftime"
+)
+
+func TestSpan(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip("time zone database unavailable:", err)
+	}
+	utc := func(year int, month time.Month, day, hour int) time.Time {
+		return time.Date(year, month, day, hour, 0, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		layout, value string
+		loc           *time.Location
+		start, end    time.Time
+	}{
+		{layout: "%Y-%m", value: "2018-07", loc: time.UTC, start: utc(2018, 7, 1, 0), end: utc(2018, 8, 1, 0)},
+		{layout: "%G-W%V", value: "2018-W28", loc: time.UTC, start: utc(2018, 7, 9, 0), end: utc(2018, 7, 16, 0)},
+		{layout: "%Y-%U", value: "2021-00", loc: time.UTC, start: utc(2021, 1, 1, 0), end: utc(2021, 1, 3, 0)},
+		{layout: "%Y", value: "2018", loc: time.UTC, start: utc(2018, 1, 1, 0), end: utc(2019, 1, 1, 0)},
+		{layout: "%G", value: "2021", loc: time.UTC, start: utc(2021, 1, 4, 0), end: utc(2022, 1, 3, 0)},
+		{layout: "%C", value: "20", loc: time.UTC, start: utc(2000, 1, 1, 0), end: utc(2100, 1, 1, 0)},
+		{layout: "%Y-%j", value: "2018-190", loc: time.UTC, start: utc(2018, 7, 9, 0), end: utc(2018, 7, 10, 0)},
+		{layout: "%F %I %p", value: "2018-07-09 01 PM", loc: time.UTC, start: utc(2018, 7, 9, 13), end: utc(2018, 7, 9, 14)},
+		{layout: "%s", value: "1531142055", loc: time.UTC, start: time.Unix(1531142055, 0), end: time.Unix(1531142056, 0)},
+		{layout: "%F", value: "2018-07-09", loc: newYork, start: utc(2018, 7, 9, 4), end: utc(2018, 7, 10, 4)},
+		{layout: "%F", value: "2024-03-10", loc: newYork, start: utc(2024, 3, 10, 5), end: utc(2024, 3, 11, 4)},
+		{layout: "%F %H", value: "2024-11-03 01", loc: newYork, start: utc(2024, 11, 3, 5), end: utc(2024, 11, 3, 7)},
+		{layout: "%F %H %Z", value: "2024-11-03 01 EST", loc: newYork, start: utc(2024, 11, 3, 6), end: utc(2024, 11, 3, 7)},
+	}
+
+	for _, tt := range tests {
+		start, end, err := strftime.Span(tt.layout, tt.value, tt.loc)
+		if err != nil {
+			t.Errorf("Span(%q, %q): unexpected error: %v", tt.layout, tt.value, err)
+			continue
+		}
+		if !start.Equal(tt.start) || !end.Equal(tt.end) {
+			t.Errorf("Span(%q, %q): expected: [%v, %v); actual: [%v, %v)", tt.layout, tt.value, tt.start, tt.end, start, end)
+		}
+	}
+}
+
+func TestSpanErrors(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip("time zone database unavailable:", err)
+	}
+
+	tests := []struct {
+		layout, value string
+		errMsg        string
+	}{
+		{layout: "%m-%d", value: "07-09", errMsg: `parsing time "07-09": layout does not name a single span of time`},
+		{layout: "%Y %H", value: "2018 13", errMsg: `parsing time "2018 13": layout does not name a single span of time`},
+		{layout: "app.log", value: "app.log", errMsg: `parsing time "app.log": layout does not name a single span of time`},
+		{layout: "%Y-%m", value: "2018-13", errMsg: `parsing time "2018-13" as "%Y-%m": cannot parse "13" as "%m"`},
+		{layout: "%F %H", value: "2024-03-10 02", errMsg: `parsing time "2024-03-10 02": time does not exist in America/New_York`},
+	}
+
+	for _, tt := range tests {
+		_, _, err := strftime.Span(tt.layout, tt.value, newYork)
+		if err == nil {
+			t.Errorf("Span(%q, %q): expected error %q", tt.layout, tt.value, tt.errMsg)
+		} else if err.Error() != tt.errMsg {
+			t.Errorf("Span(%q, %q): expected error: %q; actual: %q", tt.layout, tt.value, tt.errMsg, err.Error())
+		}
+	}
+}
+
+// TestSpanFarFuture checks a value past the end of the transition table,
+// where daylight saving time follows a rule instead.
+func TestSpanFarFuture(t *testing.T) {
+	london, err := time.LoadLocation("Europe/London")
+	if err != nil {
+		t.Skip("time zone database unavailable:", err)
+	}
+	expectedStart := time.Date(2040, 1, 1, 0, 0, 0, 0, london)
+	expectedEnd := time.Date(2041, 1, 1, 0, 0, 0, 0, london)
+
+	var start, end time.Time
+	if !within(5*time.Second, func() { start, end, err = strftime.Span("%Y", "2040", london) }) {
+		t.Fatalf("Span(%q, %q) did not return", "%Y", "2040")
+	}
+	if err != nil {
+		t.Fatalf("Span(%q, %q): unexpected error: %v", "%Y", "2040", err)
+	}
+	if !start.Equal(expectedStart) || !end.Equal(expectedEnd) {
+		t.Errorf("Span(%q, %q): expected: [%v, %v); actual: [%v, %v)", "%Y", "2040", expectedStart, expectedEnd, start, end)
+	}
+}
@@ -0,0 +1,93 @@
+// Copyright 2018 Timon Wong. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package strftime
+
+import (
+	"sync"
+	"time"
+)
+
+// SpecifierFunc appends the result of a custom specifier for t to b and
+// returns the extended buffer.
+type SpecifierFunc func(b []byte, t time.Time) []byte
+
+// Specifiers is a set of custom specifiers, used through Options.
+//
+// A custom specifier is a '%' directly followed by its name, which is one
+// or more bytes. Built-in specifiers always take precedence, so a name is
+// only looked up where the layout holds an unknown specifier; when several
+// names match there, the longest one wins. Flags and field widths do not
+// apply to custom specifiers.
+//
+// A Specifiers set may be used by multiple goroutines at once, but must not
+// be modified while in use; Clone it to derive an extended set instead.
+type Specifiers struct {
+	funcs  map[string]SpecifierFunc
+	maxLen int // longest registered name
+}
+
+// NewSpecifiers returns an empty set of custom specifiers.
+func NewSpecifiers() *Specifiers {
+	return &Specifiers{funcs: make(map[string]SpecifierFunc)}
+}
+
+// Clone returns a copy of s that can be extended independently.
+func (s *Specifiers) Clone() *Specifiers {
+	c := &Specifiers{funcs: make(map[string]SpecifierFunc, len(s.funcs)), maxLen: s.maxLen}
+	for name, fn := range s.funcs {
+		c.funcs[name] = fn
+	}
+	return c
+}
+
+// Register adds the custom specifier name, given without the leading '%',
+// replacing any previous one of the same name. It panics if name is empty
+// or fn is nil.
+func (s *Specifiers) Register(name string, fn SpecifierFunc) {
+	if name == "" {
+		panic("strftime: Register with empty specifier name")
+	}
+	if fn == nil {
+		panic("strftime: Register of nil func for specifier " + quote(name))
+	}
+	s.funcs[name] = fn
+	if len(name) > s.maxLen {
+		s.maxLen = len(name)
+	}
+}
+
+// appendFunc appends the output of fn to b. fn writes to a pooled buffer
+// of its own rather than to b, so that passing b to an unknown func does
+// not force the stack buffers of Format and friends to the heap.
+func appendFunc(b []byte, fn SpecifierFunc, t time.Time) []byte {
+	p := scratchPool.Get().(*[]byte)
+	out := fn((*p)[:0], t)
+	b = append(b, out...)
+	*p = out[:0]
+	scratchPool.Put(p)
+	return b
+}
+
+var scratchPool = sync.Pool{
+	New: func() interface{} { return new([]byte) },
+}
+
+// lookup returns the custom specifier with the longest name that prefixes
+// text, the layout following a '%', along with the length of the name.
+func (s *Specifiers) lookup(text string) (SpecifierFunc, int) {
+	if s == nil {
+		return nil, 0
+	}
+	n := s.maxLen
+	if n > len(text) {
+		n = len(text)
+	}
+	for ; n > 0; n-- {
+		if fn, ok := s.funcs[text[:n]]; ok {
+			return fn, n
+		}
+	}
+	return nil, 0
+}
@@ -0,0 +1,98 @@
+// Copyright 2018 Timon Wong. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package strftime_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/imperfectgo/go-strftime"
+)
+
+func TestSpecifiers(t *testing.T) {
+	base := strftime.NewSpecifiers()
+	// Fiscal weeks start on the first Monday of July.
+	base.Register("K", func(b []byte, t time.Time) []byte {
+		return strftime.AppendFormat(b, t, "FW%W")
+	})
+	base.Register("@", func(b []byte, t time.Time) []byte {
+		return append(b, "ABC"[t.Hour()/8])
+	})
+	base.Register("@@", func(b []byte, t time.Time) []byte {
+		return append(b, "shift-"...)
+	})
+	// Built-in specifiers win, so this is never used.
+	base.Register("Y", func(b []byte, t time.Time) []byte {
+		return append(b, "never"...)
+	})
+
+	ext := base.Clone()
+	ext.Register("K", func(b []byte, t time.Time) []byte {
+		return append(b, "override"...)
+	})
+	ext.Register("Lshift", func(b []byte, t time.Time) []byte {
+		return append(b, "S"...)
+	})
+
+	cases := []struct {
+		opts     *strftime.Options
+		layout   string
+		expected string
+	}{
+		{opts: &strftime.Options{Specifiers: base}, layout: "%Y %K %@ %@@%@", expected: "2018 FW28 B shift-B"},
+		{opts: &strftime.Options{Specifiers: base}, layout: "%Lshift %-K", expected: "%Lshift %-K"},
+		{opts: &strftime.Options{Specifiers: base, UnknownSpecifier: strftime.UnknownDrop}, layout: "%K%J%@", expected: "FW28B"},
+		{opts: &strftime.Options{Specifiers: ext}, layout: "%K %Lshift %Lshif %@", expected: "override S %Lshif B"},
+		{opts: &strftime.Options{Specifiers: ext, Locale: strftime.LocaleDeDE}, layout: "%x %K", expected: "09.07.2018 override"},
+		{opts: &strftime.Options{}, layout: "%K", expected: "%K"},
+	}
+
+	for i := range cases {
+		tt := cases[i]
+		t.Run(tt.layout, func(t *testing.T) {
+			t.Parallel()
+			if actual := tt.opts.Format(t1, tt.layout); actual != tt.expected {
+				t.Errorf("Test layout `%s`: expected: %q; actual: %q", tt.layout, tt.expected, actual)
+			}
+			l, err := tt.opts.Compile(tt.layout)
+			if err != nil {
+				t.Fatalf("Compile(%q): unexpected error: %v", tt.layout, err)
+			}
+			if actual := l.Format(t1); actual != tt.expected {
+				t.Errorf("Test compiled layout `%s`: expected: %q; actual: %q", tt.layout, tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestSpecifiersRegisterPanics(t *testing.T) {
+	for _, name := range []string{"", "K"} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("Register(%q): expected panic", name)
+				}
+			}()
+			var fn strftime.SpecifierFunc
+			if name == "" {
+				fn = func(b []byte, t time.Time) []byte { return b }
+			}
+			strftime.NewSpecifiers().Register(name, fn)
+		}()
+	}
+}
+
+func TestSpecifiersAllocs(t *testing.T) {
+	s := strftime.NewSpecifiers()
+	s.Register("K", func(b []byte, t time.Time) []byte { return append(b, 'k') })
+	opts := &strftime.Options{Specifiers: s}
+	var buf [64]byte
+	allocs := testing.AllocsPerRun(100, func() {
+		opts.AppendFormat(buf[:0], t1, "%F %K %T")
+	})
+	if allocs != 0 {
+		t.Errorf("expected no allocations; actual: %v", allocs)
+	}
+}
@@ -0,0 +1,143 @@
+// Copyright 2018 Timon Wong. All rights reserved.
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !strftime_unsafe || appengine || js || wasip1 || tinygo
+// +build !strftime_unsafe appengine js wasip1 tinygo
+
+package strftime
+
+import (
+	"time"
+)
+
+// The calendar computations below only use the exported time API. They
+// count seconds from January 1 of the absolute zero year, a Monday far
+// enough in the past for every time.Time to land on a positive value,
+// and split the count with the same 400, 100 and 4 year cycles as the
+// time package.
+
+const (
+	secondsPerMinute = 60
+	secondsPerHour   = 60 * secondsPerMinute
+	secondsPerDay    = 24 * secondsPerHour
+	secondsPerWeek   = 7 * secondsPerDay
+	daysPer400Years  = 365*400 + 97
+	daysPer100Years  = 365*100 + 24
+	daysPer4Years    = 365*4 + 1
+)
+
+const (
+	// The unsigned zero year for internal calculations.
+	// Must be 1 mod 400, and times before it will not compute correctly,
+	// but otherwise can be changed at will.
+	absoluteZeroYear = -292277022399
+
+	// Offsets to convert between the internal year 1 epoch of the time
+	// package, the Unix epoch and the absolute zero year.
+	internalYear             = 1
+	absoluteToInternal int64 = (absoluteZeroYear - internalYear) * 365.2425 * secondsPerDay
+	internalToAbsolute       = -absoluteToInternal
+	unixToInternal     int64 = (1969*365 + 1969/4 - 1969/100 + 1969/400) * secondsPerDay
+	unixToAbsolute           = unixToInternal + internalToAbsolute
+)
+
+// locabs returns the zone name, the zone offset in seconds east of UTC
+// and the seconds since the absolute zero year of t, in its own location.
+func locabs(t *time.Time) (name string, offset int, abs uint64) {
+	name, offset = t.Zone()
+	abs = uint64(t.Unix() + int64(offset) + unixToAbsolute)
+	return
+}
+
+// absDate returns the year, month, day and zero-based day of the year
+// of abs. With full unset, the month and day are left zero.
+func absDate(abs uint64, full bool) (year int, month time.Month, day int, yday int) {
+	// Split into time and day.
+	d := abs / secondsPerDay
+
+	// Account for 400 year cycles.
+	n := d / daysPer400Years
+	y := 400 * n
+	d -= daysPer400Years * n
+
+	// Cut off 100-year cycles.
+	// The last cycle has one extra leap year, so on the last day
+	// of that year, day / daysPer100Years will be 4 instead of 3.
+	// Cut it back down to 3 by subtracting n>>2.
+	n = d / daysPer100Years
+	n -= n >> 2
+	y += 100 * n
+	d -= daysPer100Years * n
+
+	// Cut off 4-year cycles.
+	// The last cycle has a missing leap year, which does not
+	// affect the computation.
+	n = d / daysPer4Years
+	y += 4 * n
+	d -= daysPer4Years * n
+
+	// Cut off years within a 4-year cycle.
+	// The last year is a leap year, so on the last day of that year,
+	// day / 365 will be 4 instead of 3. Cut it back down to 3
+	// by subtracting n>>2.
+	n = d / 365
+	n -= n >> 2
+	y += n
+	d -= 365 * n
+
+	year = int(int64(y) + absoluteZeroYear)
+	yday = int(d)
+
+	if !full {
+		return
+	}
+
+	day = yday
+	if isLeap(year) {
+		switch {
+		case day > 31+29-1:
+			// After leap day; pretend it wasn't there.
+			day--
+		case day == 31+29-1:
+			// Leap day.
+			month = time.February
+			day = 29
+			return
+		}
+	}
+
+	// Estimate month on assumption that every month has 31 days.
+	// The estimate may be too low by at most one month, so adjust.
+	month = time.Month(day / 31)
+	end := int(daysBefore[month+1])
+	var begin int
+	if day >= end {
+		month++
+		begin = end
+	} else {
+		begin = int(daysBefore[month])
+	}
+
+	month++ // because January is 1
+	day = day - begin + 1
+	return
+}
+
+// absClock returns the hour, minute and second within the day of abs.
+func absClock(abs uint64) (hour, min, sec int) {
+	sec = int(abs % secondsPerDay)
+	hour = sec / secondsPerHour
+	sec -= hour * secondsPerHour
+	min = sec / secondsPerMinute
+	sec -= min * secondsPerMinute
+	return
+}
+
+// absWeekday returns the day of the week of abs.
+func absWeekday(abs uint64) time.Weekday {
+	// January 1 of the absolute year, like January 1 of 2001, was a Monday.
+	sec := (abs + uint64(time.Monday)*secondsPerDay) % secondsPerWeek
+	return time.Weekday(int(sec) / secondsPerDay)
+}
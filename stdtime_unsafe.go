@@ -3,7 +3,13 @@
 // Use of this source code is governed by a BSD-style
 // license that can be found in the LICENSE file.
 
-// +build !appengine,!js
+//go:build strftime_unsafe && !appengine && !js && !wasip1 && !tinygo
+// +build strftime_unsafe,!appengine,!js,!wasip1,!tinygo
+
+// The calendar functions are linked from the time package when building
+// with the strftime_unsafe tag. This relies on unexported runtime symbols
+// whose signatures change between Go releases; see stdtime.go for the
+// default implementation.
 
 package strftime
 
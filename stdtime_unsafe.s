@@ -3,7 +3,8 @@
 // Use of this source code is governed by a BSD-style
 // license that can be found in the LICENSE file.
 
+//go:build strftime_unsafe && !appengine && !js && !wasip1 && !tinygo
+// +build strftime_unsafe,!appengine,!js,!wasip1,!tinygo
+
 // Empty assembly file just make `go:linkname` work without "missing function body" error
 // See https://github.com/golang/go/issues/15006
-
-// +build !appengine,!js
@@ -0,0 +1,85 @@
+// Copyright 2018 Timon Wong. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package strftime
+
+import (
+	"time"
+)
+
+// LayoutError describes an unknown or dangling specifier in a layout,
+// or a part of a layout that cannot be translated.
+type LayoutError struct {
+	Layout    string
+	Offset    int    // byte offset of Specifier in Layout
+	Specifier string // the offending text, such as "%J" or a trailing "%"
+	Message   string // what is wrong, if not an unknown or dangling specifier
+}
+
+// Error returns the string representation of a LayoutError.
+func (e *LayoutError) Error() string {
+	what := e.Message
+	switch {
+	case what != "":
+	case e.dangling():
+		what = "dangling"
+	default:
+		what = "unknown specifier"
+	}
+	return "strftime: " + what + " " + quote(e.Specifier) + " at offset " +
+		string(appendInt(nil, e.Offset, 0)) + " in layout " + quote(e.Layout)
+}
+
+// dangling reports whether the specifier lacks its conversion character,
+// that is, whether the layout ends within it.
+func (e *LayoutError) dangling() bool {
+	if e.Offset+len(e.Specifier) != len(e.Layout) {
+		return false
+	}
+	switch c := e.Specifier[len(e.Specifier)-1]; c {
+	case '%', '-', '_', '0', '^', '#', ':':
+		return true
+	default:
+		return isDigit(c)
+	}
+}
+
+// Validate reports the first unknown or dangling specifier in layout as
+// a *LayoutError, or returns nil if Format understands the whole layout.
+func Validate(layout string) error {
+	for rest := layout; rest != ""; {
+		prefix, std, suffix := nextStdChunk(rest)
+		if std == 0 {
+			break
+		}
+		if std == stdUnknown {
+			return &LayoutError{
+				Layout:    layout,
+				Offset:    len(layout) - len(rest) + len(prefix),
+				Specifier: rest[len(prefix) : len(rest)-len(suffix)],
+			}
+		}
+		rest = suffix
+	}
+	return nil
+}
+
+// FormatStrict is like Format but returns an error, as reported by
+// Validate, instead of copying unknown or dangling specifiers through.
+func FormatStrict(t time.Time, layout string) (string, error) {
+	if err := Validate(layout); err != nil {
+		return "", err
+	}
+	return Format(t, layout), nil
+}
+
+// AppendFormatStrict is like FormatStrict but appends the textual
+// representation to b and returns the extended buffer. On error, b is
+// returned unchanged.
+func AppendFormatStrict(b []byte, t time.Time, layout string) ([]byte, error) {
+	if err := Validate(layout); err != nil {
+		return b, err
+	}
+	return AppendFormat(b, t, layout), nil
+}
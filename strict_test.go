@@ -0,0 +1,75 @@
+// Copyright 2018 Timon Wong. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package strftime_test
+
+import (
+	"testing"
+
+	"github.com/imperfectgo/go-strftime"
+)
+
+func TestValidate(t *testing.T) {
+	cases := []struct {
+		layout string
+		errMsg string
+	}{
+		{layout: "%Y-%m-%d %H:%M:%S.%f%z"},
+		{layout: "100%% %c %-d %_10H %#:z %3N %s"},
+		{layout: ""},
+		{layout: "%Y-%J", errMsg: `strftime: unknown specifier "%J" at offset 3 in layout "%Y-%J"`},
+		{layout: "%-5J%Y", errMsg: `strftime: unknown specifier "%-5J" at offset 0 in layout "%-5J%Y"`},
+		{layout: "%:Y", errMsg: `strftime: unknown specifier "%:Y" at offset 0 in layout "%:Y"`},
+		{layout: "%é", errMsg: `strftime: unknown specifier "%\xc3\xa9" at offset 0 in layout "%\xc3\xa9"`},
+		{layout: "bar%", errMsg: `strftime: dangling "%" at offset 3 in layout "bar%"`},
+		{layout: "%Y%-", errMsg: `strftime: dangling "%-" at offset 2 in layout "%Y%-"`},
+		{layout: "%:%Y", errMsg: `strftime: unknown specifier "%:" at offset 0 in layout "%:%Y"`},
+	}
+
+	for i := range cases {
+		tt := cases[i]
+		t.Run(tt.layout, func(t *testing.T) {
+			t.Parallel()
+			err := strftime.Validate(tt.layout)
+			if tt.errMsg == "" {
+				if err != nil {
+					t.Errorf("Validate(%q): unexpected error: %v", tt.layout, err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("Validate(%q): expected error", tt.layout)
+			}
+			if err.Error() != tt.errMsg {
+				t.Errorf("Validate(%q): expected error: %s; actual: %s", tt.layout, tt.errMsg, err)
+			}
+		})
+	}
+}
+
+func TestFormatStrict(t *testing.T) {
+	actual, err := strftime.FormatStrict(t1, "%Y-%m-%d %%")
+	if err != nil || actual != "2018-07-09 %" {
+		t.Errorf("FormatStrict: expected: %q; actual: %q, %v", "2018-07-09 %", actual, err)
+	}
+
+	_, err = strftime.FormatStrict(t1, "%Y-%m-%d %Q%K")
+	lerr, ok := err.(*strftime.LayoutError)
+	if !ok {
+		t.Fatalf("FormatStrict: expected *LayoutError; actual: %v", err)
+	}
+	if lerr.Offset != 11 || lerr.Specifier != "%K" {
+		t.Errorf("FormatStrict: expected %%K at offset 11; actual: %q at offset %d", lerr.Specifier, lerr.Offset)
+	}
+
+	buf := []byte("prefix ")
+	b, err := strftime.AppendFormatStrict(buf, t1, "%F %")
+	if err == nil || string(b) != "prefix " {
+		t.Errorf("AppendFormatStrict: expected error and unchanged buffer; actual: %q, %v", b, err)
+	}
+	b, err = strftime.AppendFormatStrict(buf, t1, "%F")
+	if err != nil || string(b) != "prefix 2018-07-09" {
+		t.Errorf("AppendFormatStrict: expected: %q; actual: %q, %v", "prefix 2018-07-09", b, err)
+	}
+}
@@ -0,0 +1,115 @@
+// Copyright 2018 Timon Wong. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package strftime
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+// FormatTo writes the textual representation of the time value formatted
+// according to layout to w, and returns the number of bytes written and
+// any write error encountered.
+//
+// A *bytes.Buffer is formatted into directly. Other writers implementing
+// io.ByteWriter, such as *bufio.Writer, receive the output piece by piece,
+// with literal text passed on through io.StringWriter when implemented.
+// Neither path buffers the output in between. Any other writer, such as an
+// *os.File, receives the output in a single Write from a pooled buffer.
+func FormatTo(w io.Writer, t time.Time, layout string) (int, error) {
+	const bufSize = 64
+	switch w := w.(type) {
+	case *bytes.Buffer:
+		// Format into the spare capacity of the buffer; Write then copies
+		// the result onto itself.
+		w.Grow(bufSize)
+		b := w.Bytes()
+		return w.Write(AppendFormat(b[len(b):], t, layout))
+	case *strings.Builder:
+		var b [bufSize]byte
+		return w.Write(AppendFormat(b[:0], t, layout))
+	case io.ByteWriter:
+		var f fields
+		f.init(t, nil)
+		return f.writeLayout(w, layout)
+	}
+	p := writeBufPool.Get().(*[]byte)
+	*p = AppendFormat((*p)[:0], t, layout)
+	n, err := w.Write(*p)
+	writeBufPool.Put(p)
+	return n, err
+}
+
+// writeBufPool holds the buffers FormatTo formats into for writers without
+// a path of their own. Writing the output at once keeps it to one system
+// call for a file.
+var writeBufPool = sync.Pool{
+	New: func() interface{} {
+		b := make([]byte, 0, 64)
+		return &b
+	},
+}
+
+// writeLayout is like appendLayout but writes to w. Each std value is
+// formatted into a small scratch buffer on the stack, which never reaches
+// w itself, so it does not escape.
+func (f *fields) writeLayout(w io.ByteWriter, layout string) (n int, err error) {
+	for layout != "" {
+		prefix, std, suffix := nextStdChunk(layout)
+		m, err := writeString(w, prefix)
+		n += m
+		if err != nil || std == 0 {
+			return n, err
+		}
+		stdstr := layout[len(prefix) : len(layout)-len(suffix)]
+		layout = suffix
+
+		var buf [32]byte
+		var b []byte
+		switch {
+		case std == stdUnknown:
+			b = f.appendUnknown(buf[:0], stdstr)
+		case std&stdMask == stdComposite:
+			nested := f.nested
+			f.nested = true
+			m, err = f.writeLayout(w, compositeLayout(f.loc, std>>stdValueShift, nested))
+			f.nested = nested
+			n += m
+			if err != nil {
+				return n, err
+			}
+			continue
+		default:
+			f.compute(std)
+			b = f.appendStd(buf[:0], std)
+		}
+		for _, c := range b {
+			if err := w.WriteByte(c); err != nil {
+				return n, err
+			}
+			n++
+		}
+	}
+	return n, nil
+}
+
+// writeString writes s to w, with WriteString if w implements it.
+func writeString(w io.ByteWriter, s string) (int, error) {
+	if s == "" {
+		return 0, nil
+	}
+	if sw, ok := w.(io.StringWriter); ok {
+		return sw.WriteString(s)
+	}
+	for i := 0; i < len(s); i++ {
+		if err := w.WriteByte(s[i]); err != nil {
+			return i, err
+		}
+	}
+	return len(s), nil
+}
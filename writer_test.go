@@ -0,0 +1,130 @@
+// Copyright 2018 Timon Wong. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package strftime_test
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/imperfectgo/go-strftime"
+)
+
+// byteWriter implements io.ByteWriter but not io.StringWriter.
+type byteWriter struct{ bytes.Buffer }
+
+func (w *byteWriter) Write(p []byte) (int, error) { return w.Buffer.Write(p) }
+
+func (w *byteWriter) WriteByte(c byte) error { return w.Buffer.WriteByte(c) }
+
+// plainWriter implements io.Writer only.
+type plainWriter struct{ bytes.Buffer }
+
+func (w *plainWriter) Write(p []byte) (int, error) { return w.Buffer.Write(p) }
+
+// stringWriter implements io.StringWriter but not io.ByteWriter, as
+// *os.File does.
+type stringWriter struct{ bytes.Buffer }
+
+func (w *stringWriter) Write(p []byte) (int, error) { return w.Buffer.Write(p) }
+
+func (w *stringWriter) WriteString(s string) (int, error) { return w.Buffer.WriteString(s) }
+
+// limitWriter fails once n bytes have been written.
+type limitWriter struct{ n int }
+
+var errLimit = errors.New("limit reached")
+
+func (w *limitWriter) Write(p []byte) (int, error) {
+	for i := range p {
+		if err := w.WriteByte(p[i]); err != nil {
+			return i, err
+		}
+	}
+	return len(p), nil
+}
+
+func (w *limitWriter) WriteByte(c byte) error {
+	if w.n == 0 {
+		return errLimit
+	}
+	w.n--
+	return nil
+}
+
+func TestFormatTo(t *testing.T) {
+	for i := range tc {
+		tt := tc[i]
+		t.Run(tt.layout, func(t *testing.T) {
+			t.Parallel()
+			var sb strings.Builder
+			var bb bytes.Buffer
+			var bw byteWriter
+			var pw plainWriter
+			var sw stringWriter
+			var out bytes.Buffer
+			buffered := bufio.NewWriter(&out)
+			for _, w := range []io.Writer{&sb, &bb, &bw, &pw, &sw, buffered} {
+				n, err := strftime.FormatTo(w, tt.time, tt.layout)
+				if err != nil || n != len(tt.expected) {
+					t.Errorf("FormatTo(%T, %q): expected: %d, nil; actual: %d, %v", w, tt.layout, len(tt.expected), n, err)
+				}
+			}
+			buffered.Flush()
+			for _, actual := range []string{sb.String(), bb.String(), bw.String(), pw.String(), sw.String(), out.String()} {
+				if actual != tt.expected {
+					t.Errorf("FormatTo(%q): expected: %q; actual: %q", tt.layout, tt.expected, actual)
+				}
+			}
+		})
+	}
+}
+
+func TestFormatToError(t *testing.T) {
+	for _, limit := range []int{0, 3, 7, 12} {
+		w := &limitWriter{n: limit}
+		n, err := strftime.FormatTo(w, t1, "%F %T")
+		if err != errLimit || n != limit {
+			t.Errorf("FormatTo with limit %d: expected: %d, %v; actual: %d, %v", limit, limit, errLimit, n, err)
+		}
+	}
+}
+
+func TestFormatToAllocs(t *testing.T) {
+	const layout = "%Y-%m-%dT%H:%M:%S.%f%z %c"
+	var bb bytes.Buffer
+	var sb strings.Builder
+	var pw plainWriter
+	var sw stringWriter
+	buffered := bufio.NewWriter(ioutil.Discard)
+	writers := []struct {
+		name  string
+		w     io.Writer
+		reset func()
+	}{
+		{name: "bytes.Buffer", w: &bb, reset: bb.Reset},
+		// A strings.Builder cannot be reset without dropping its buffer,
+		// so grow it once to hold every run.
+		{name: "strings.Builder", w: &sb, reset: func() {}},
+		{name: "bufio.Writer", w: buffered, reset: func() { buffered.Flush() }},
+		{name: "io.Writer", w: &pw, reset: pw.Reset},
+		{name: "io.StringWriter", w: &sw, reset: sw.Reset},
+	}
+	sb.Grow(1 << 16)
+	for _, tt := range writers {
+		tt.reset()
+		allocs := testing.AllocsPerRun(100, func() {
+			tt.reset()
+			strftime.FormatTo(tt.w, t1, layout)
+		})
+		if allocs != 0 {
+			t.Errorf("FormatTo(%s): expected no allocations; actual: %v", tt.name, allocs)
+		}
+	}
+}
@@ -0,0 +1,10 @@
+module github.com/imperfectgo/go-strftime/zapx
+
+go 1.19
+
+require (
+	github.com/imperfectgo/go-strftime v1.1.0
+	go.uber.org/zap v1.28.0
+)
+
+require go.uber.org/multierr v1.10.0 // indirect
@@ -0,0 +1,40 @@
+// Copyright 2018 Timon Wong. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package zapx formats the times of go.uber.org/zap log entries with a
+// strftime layout. It is a module of its own, so that the strftime package
+// does not depend on zap.
+package zapx
+
+import (
+	"sync"
+	"time"
+
+	strftime "github.com/imperfectgo/go-strftime"
+	"go.uber.org/zap/zapcore"
+)
+
+// TimeEncoder returns a zapcore.TimeEncoder that formats times with l:
+//
+//	cfg := zap.NewProductionEncoderConfig()
+//	cfg.EncodeTime = zapx.TimeEncoder(strftime.MustCompile("%Y-%m-%d %H:%M:%S.%3N"))
+//
+// Times are formatted into a pooled buffer and handed over with
+// AppendByteString, which copies them into the encoder's own buffer, so
+// encoding does not allocate.
+func TimeEncoder(l *strftime.Layout) zapcore.TimeEncoder {
+	return func(t time.Time, enc zapcore.PrimitiveArrayEncoder) {
+		p := bufPool.Get().(*[]byte)
+		*p = l.AppendFormat((*p)[:0], t)
+		enc.AppendByteString(*p)
+		bufPool.Put(p)
+	}
+}
+
+var bufPool = sync.Pool{
+	New: func() interface{} {
+		b := make([]byte, 0, 64)
+		return &b
+	},
+}
@@ -0,0 +1,64 @@
+// Copyright 2018 Timon Wong. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package zapx_test
+
+import (
+	"testing"
+	"time"
+
+	strftime "github.com/imperfectgo/go-strftime"
+	"github.com/imperfectgo/go-strftime/zapx"
+	"go.uber.org/zap/zapcore"
+)
+
+var t1 = time.Date(2008, 9, 3, 20, 4, 26, 654321000, time.UTC)
+
+func newEncoder(layout string) zapcore.Encoder {
+	return zapcore.NewJSONEncoder(zapcore.EncoderConfig{
+		TimeKey:    "ts",
+		MessageKey: "msg",
+		EncodeTime: zapx.TimeEncoder(strftime.MustCompile(layout)),
+	})
+}
+
+func TestTimeEncoder(t *testing.T) {
+	tests := []struct {
+		layout   string
+		expected string
+	}{
+		{layout: "%Y-%m-%d %H:%M:%S.%3N", expected: `{"ts":"2008-09-03 20:04:26.654","msg":"hello","at":"2008-09-03 20:04:26.654"}` + "\n"},
+		{layout: `%s "%a"`, expected: `{"ts":"1220472266 \"Wed\"","msg":"hello","at":"1220472266 \"Wed\""}` + "\n"},
+	}
+
+	for _, tt := range tests {
+		enc := newEncoder(tt.layout)
+		buf, err := enc.EncodeEntry(zapcore.Entry{Time: t1, Message: "hello"}, []zapcore.Field{
+			{Key: "at", Type: zapcore.TimeType, Integer: t1.UnixNano(), Interface: time.UTC},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if actual := buf.String(); actual != tt.expected {
+			t.Errorf("layout %q: expected: %q; actual: %q", tt.layout, tt.expected, actual)
+		}
+		buf.Free()
+	}
+}
+
+func TestTimeEncoderAllocs(t *testing.T) {
+	if raceEnabled {
+		t.Skip("sync.Pool drops items at random under the race detector")
+	}
+
+	enc := newEncoder("%Y-%m-%d %H:%M:%S.%3N")
+	entry := zapcore.Entry{Time: t1}
+	allocs := testing.AllocsPerRun(100, func() {
+		buf, _ := enc.EncodeEntry(entry, nil)
+		buf.Free()
+	})
+	if allocs != 0 {
+		t.Errorf("expected no allocations; actual: %v", allocs)
+	}
+}
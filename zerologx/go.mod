@@ -0,0 +1,14 @@
+module github.com/imperfectgo/go-strftime/zerologx
+
+go 1.23
+
+require (
+	github.com/imperfectgo/go-strftime v1.1.0
+	github.com/rs/zerolog v1.35.1
+)
+
+require (
+	github.com/mattn/go-colorable v0.1.14 // indirect
+	github.com/mattn/go-isatty v0.0.20 // indirect
+	golang.org/x/sys v0.29.0 // indirect
+)
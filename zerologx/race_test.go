@@ -0,0 +1,12 @@
+// Copyright 2018 Timon Wong. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build race
+// +build race
+
+package zerologx_test
+
+// raceEnabled reports whether the race detector is on. It makes sync.Pool
+// drop items at random, so pooled buffers are then allocated anew.
+const raceEnabled = true
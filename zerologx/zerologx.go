@@ -0,0 +1,50 @@
+// Copyright 2018 Timon Wong. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package zerologx formats the timestamps of github.com/rs/zerolog events
+// with a strftime layout. It is a module of its own, so that the strftime
+// package does not depend on zerolog.
+package zerologx
+
+import (
+	"sync"
+
+	strftime "github.com/imperfectgo/go-strftime"
+	"github.com/rs/zerolog"
+)
+
+// TimestampHook is a zerolog.Hook that adds the timestamp field the way
+// Context.Timestamp does, under zerolog.TimestampFieldName and at the time
+// given by zerolog.TimestampFunc, but formatted with a strftime layout
+// instead of zerolog.TimeFieldFormat. Use it in place of Timestamp:
+//
+//	l := strftime.MustCompile("%Y-%m-%d %H:%M:%S.%3N")
+//	logger := zerolog.New(os.Stdout).Hook(zerologx.NewTimestampHook(l))
+//
+// The timestamp is formatted into a pooled buffer and added with
+// Event.Bytes, so adding it does not allocate. With the binary_log build
+// tag, zerolog encodes it as a CBOR byte string.
+type TimestampHook struct {
+	l *strftime.Layout
+}
+
+// NewTimestampHook returns a TimestampHook that formats timestamps with l.
+func NewTimestampHook(l *strftime.Layout) TimestampHook {
+	return TimestampHook{l: l}
+}
+
+// Run adds the timestamp field to e.
+func (h TimestampHook) Run(e *zerolog.Event, level zerolog.Level, msg string) {
+	p := bufPool.Get().(*[]byte)
+	*p = h.l.AppendFormat((*p)[:0], zerolog.TimestampFunc())
+	e.Bytes(zerolog.TimestampFieldName, *p)
+	bufPool.Put(p)
+}
+
+var bufPool = sync.Pool{
+	New: func() interface{} {
+		b := make([]byte, 0, 64)
+		return &b
+	},
+}
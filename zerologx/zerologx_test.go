@@ -0,0 +1,56 @@
+// Copyright 2018 Timon Wong. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package zerologx_test
+
+import (
+	"bytes"
+	"io"
+	"testing"
+	"time"
+
+	strftime "github.com/imperfectgo/go-strftime"
+	"github.com/imperfectgo/go-strftime/zerologx"
+	"github.com/rs/zerolog"
+)
+
+var t1 = time.Date(2008, 9, 3, 20, 4, 26, 654321000, time.UTC)
+
+func withTimestamp(t *testing.T) {
+	saved := zerolog.TimestampFunc
+	zerolog.TimestampFunc = func() time.Time { return t1 }
+	t.Cleanup(func() { zerolog.TimestampFunc = saved })
+}
+
+func TestTimestampHook(t *testing.T) {
+	withTimestamp(t)
+
+	var buf bytes.Buffer
+	hook := zerologx.NewTimestampHook(strftime.MustCompile("%Y-%m-%d %H:%M:%S.%3N"))
+	logger := zerolog.New(&buf).Hook(hook)
+	logger.Info().Str("k", "v").Msg("hello")
+	logger.Debug().Msg("")
+
+	expected := `{"level":"info","k":"v","time":"2008-09-03 20:04:26.654","message":"hello"}` + "\n" +
+		`{"level":"debug","time":"2008-09-03 20:04:26.654"}` + "\n"
+	if actual := buf.String(); actual != expected {
+		t.Errorf("expected: %q; actual: %q", expected, actual)
+	}
+}
+
+func TestTimestampHookAllocs(t *testing.T) {
+	if raceEnabled {
+		t.Skip("sync.Pool drops items at random under the race detector")
+	}
+	withTimestamp(t)
+
+	hook := zerologx.NewTimestampHook(strftime.MustCompile("%Y-%m-%d %H:%M:%S.%3N"))
+	logger := zerolog.New(io.Discard).Hook(hook)
+	allocs := testing.AllocsPerRun(100, func() {
+		logger.Info().Msg("hello")
+	})
+	if allocs != 0 {
+		t.Errorf("expected no allocations; actual: %v", allocs)
+	}
+}
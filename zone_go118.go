@@ -0,0 +1,84 @@
+// Copyright 2018 Timon Wong. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !go1.19
+// +build !go1.19
+
+package strftime
+
+import "time"
+
+// Before Go 1.19, time.Time.ZoneBounds is missing and zone transitions are
+// searched for instead: a day at a time, then bisected to the second.
+// Transitions that change neither the zone name nor the offset are not
+// found, nor are pairs of transitions less than a day apart that cancel
+// out. Without a bound, the search covers a year.
+
+const zoneHorizon = 366 * 24 * time.Hour
+
+// zoneStart returns the latest zone transition of t's location from lo
+// through t. A zero lo places no bound.
+func zoneStart(t, lo time.Time) (time.Time, bool) {
+	if lo.IsZero() {
+		lo = t.Add(-zoneHorizon)
+	}
+	for hi := t; hi.After(lo); {
+		x := hi.Add(-24 * time.Hour)
+		if x.Before(lo) {
+			x = lo.Add(-time.Second)
+		}
+		if !sameZone(x, t) {
+			z := bisectZone(x, hi)
+			if z.Before(lo) || z.After(t) {
+				break
+			}
+			return z, true
+		}
+		hi = x
+	}
+	return time.Time{}, false
+}
+
+// zoneEnd returns the earliest zone transition of t's location after t and
+// not after hi. A zero hi places no bound.
+func zoneEnd(t, hi time.Time) (time.Time, bool) {
+	if hi.IsZero() {
+		hi = t.Add(zoneHorizon)
+	}
+	for lo := t; lo.Before(hi); {
+		x := lo.Add(24 * time.Hour)
+		if x.After(hi) {
+			x = hi
+		}
+		if !sameZone(x, t) {
+			if z := bisectZone(lo, x); z.After(t) {
+				return z, true
+			}
+			break
+		}
+		lo = x
+	}
+	return time.Time{}, false
+}
+
+// bisectZone returns the first second after lo in the zone of hi, given
+// that lo and hi are in different zones. Transitions fall on whole seconds.
+func bisectZone(lo, hi time.Time) time.Time {
+	l, h := lo.Unix(), hi.Unix()
+	for h-l > 1 {
+		m := l + (h-l)/2
+		if sameZone(time.Unix(m, 0).In(hi.Location()), hi) {
+			h = m
+		} else {
+			l = m
+		}
+	}
+	return time.Unix(h, 0).In(hi.Location())
+}
+
+func sameZone(a, b time.Time) bool {
+	aName, aOffset := a.Zone()
+	bName, bOffset := b.Zone()
+	return aName == bName && aOffset == bOffset
+}
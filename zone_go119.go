@@ -0,0 +1,36 @@
+// Copyright 2018 Timon Wong. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build go1.19
+// +build go1.19
+
+package strftime
+
+import "time"
+
+// zoneStart returns the latest zone transition of t's location from lo
+// through t. A zero lo places no bound.
+func zoneStart(t, lo time.Time) (time.Time, bool) {
+	start, _ := t.ZoneBounds()
+	if start.IsZero() || start.After(t) || start.Before(lo) {
+		return time.Time{}, false
+	}
+	return start, true
+}
+
+// zoneEnd returns the earliest zone transition of t's location after t and
+// not after hi. A zero hi places no bound.
+func zoneEnd(t, hi time.Time) (time.Time, bool) {
+	_, end := t.ZoneBounds()
+	if !end.IsZero() && !end.After(t) {
+		// Past the end of the transition table, ZoneBounds may report an
+		// end at or before t near the end of a year, where no transition
+		// falls. The zone holds into the next day.
+		_, end = t.Add(24 * time.Hour).ZoneBounds()
+	}
+	if end.IsZero() || !end.After(t) || (!hi.IsZero() && end.After(hi)) {
+		return time.Time{}, false
+	}
+	return end, true
+}